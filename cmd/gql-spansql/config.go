@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/nktks/gql-spansql/gqlspansql"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigs are the config files loaded when -config is not given.
+var defaultConfigs = []string{"gql-spansql.yaml", "gql-spansql.yml", "gql-spansql.json"}
+
+// config is the content of the configuration file.
+type config struct {
+	gqlspansql.Options `yaml:",inline"`
+	// Schema is the globs of the input schema.
+	Schema    []string `yaml:"schema" json:"schema"`
+	Dialect   string   `yaml:"dialect" json:"dialect"`
+	Emit      string   `yaml:"emit" json:"emit"`
+	GoPackage string   `yaml:"goPackage" json:"goPackage"`
+	// Output is the path which DDL is written to. stdout if empty.
+	Output string `yaml:"output" json:"output"`
+	// GoOutput is the path which Go structs are written to. stdout if empty.
+	GoOutput string `yaml:"goOutput" json:"goOutput"`
+}
+
+// loadConfig loads the config file at path, or the default config file if path is empty.
+// it returns an empty config if path is empty and no default config file exists.
+// unknown keys are errors, and the relative paths in the file are relative to the file.
+func loadConfig(path string) (*config, error) {
+	cfg := &config{}
+	if path == "" {
+		for _, p := range defaultConfigs {
+			if _, err := os.Stat(p); err == nil {
+				path = p
+				break
+			}
+		}
+		if path == "" {
+			return cfg, nil
+		}
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Read from config failed: %w", err)
+	}
+	if filepath.Ext(path) == ".json" {
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(cfg)
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(b))
+		dec.KnownFields(true)
+		if err = dec.Decode(cfg); err == io.EOF {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	dir := filepath.Dir(path)
+	for i, s := range cfg.Schema {
+		cfg.Schema[i] = resolvePath(dir, s)
+	}
+	cfg.Output = resolvePath(dir, cfg.Output)
+	cfg.GoOutput = resolvePath(dir, cfg.GoOutput)
+	return cfg, nil
+}
+
+// resolvePath returns path relative to dir unless it is absolute or empty.
+func resolvePath(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// override overrides the config by the flags set in the command line.
+func (cfg *config) override() error {
+	types, err := gqlspansql.ParseScalarTypes(scalars)
+	if err != nil {
+		return err
+	}
+	if len(types) > 0 && cfg.ScalarTypes == nil {
+		cfg.ScalarTypes = map[string]string{}
+	}
+	for name, t := range types {
+		cfg.ScalarTypes[name] = t
+	}
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "s":
+			cfg.Schema = schemas
+		case "loose":
+			cfg.Loose = *loose
+		case "strict":
+			cfg.Strict = *strict
+		case "created-column-name":
+			cfg.CreatedColumnName = *createdName
+		case "updated-column-name":
+			cfg.UpdatedColumnName = *updatedName
+		case "table-case":
+			cfg.TableCase = *tableCase
+		case "column-case":
+			cfg.ColumnCase = *columnCase
+		case "foreign-key":
+			cfg.ForeignKeys = *foreignKey
+		case "many-to-many":
+			cfg.ManyToMany = *manyToMany
+		case "include":
+			cfg.IncludeTypes = includes
+		case "exclude":
+			cfg.ExcludeTypes = excludes
+		case "embedded":
+			cfg.EmbeddedTypes = embeddeds
+		case "json":
+			cfg.JSON = *jsonMode
+		case "json-fields":
+			cfg.JSONFields = jsonFields
+		case "relay":
+			cfg.Relay = *relay
+		case "fields-with-arguments":
+			cfg.FieldsWithArguments = *argFields
+		case "dialect":
+			cfg.Dialect = *dialect
+		case "emit":
+			cfg.Emit = *emit
+		case "go-package":
+			cfg.GoPackage = *goPackage
+		case "o":
+			cfg.Output = *output
+			cfg.GoOutput = *output
+		}
+	})
+	if cfg.Dialect == "" {
+		cfg.Dialect = *dialect
+	}
+	if cfg.Emit == "" {
+		cfg.Emit = *emit
+	}
+	if cfg.GoPackage == "" {
+		cfg.GoPackage = *goPackage
+	}
+	return nil
+}
+
+// write writes s to path, or to stdout if path is empty.
+func write(path, s string) error {
+	if path == "" {
+		_, err := fmt.Print(s)
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(path, []byte(s), 0o644); err != nil {
+		return fmt.Errorf("Write to file failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, dir, name, body string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(body), 0o644))
+	return path
+}
+
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() {
+		require.NoError(t, os.Chdir(wd))
+	})
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("yaml", func(t *testing.T) {
+		dir := t.TempDir()
+		path := writeConfig(t, dir, "config.yaml", `schema:
+  - schema/*.graphql
+tableCase: snake
+foreignKey: true
+scalars:
+  DateTime: TIMESTAMP
+exclude:
+  - "*Payload"
+dialect: postgresql
+`)
+		cfg, err := loadConfig(path)
+		require.NoError(t, err)
+		require.Equal(t, []string{filepath.Join(dir, "schema/*.graphql")}, cfg.Schema)
+		require.Equal(t, "snake", cfg.TableCase)
+		require.True(t, cfg.ForeignKeys)
+		require.Equal(t, map[string]string{"DateTime": "TIMESTAMP"}, cfg.ScalarTypes)
+		require.Equal(t, []string{"*Payload"}, cfg.ExcludeTypes)
+		require.Equal(t, "postgresql", cfg.Dialect)
+	})
+	t.Run("json", func(t *testing.T) {
+		dir := t.TempDir()
+		path := writeConfig(t, dir, "config.json", `{
+  "schema": ["schema.graphql", "/schema/common.graphql"],
+  "columnCase": "snake",
+  "manyToMany": true,
+  "scalars": {"Email": "STRING(256)"},
+  "goOutput": "model/model.go"
+}`)
+		cfg, err := loadConfig(path)
+		require.NoError(t, err)
+		require.Equal(t, []string{filepath.Join(dir, "schema.graphql"), "/schema/common.graphql"}, cfg.Schema)
+		require.Equal(t, "snake", cfg.ColumnCase)
+		require.True(t, cfg.ManyToMany)
+		require.Equal(t, map[string]string{"Email": "STRING(256)"}, cfg.ScalarTypes)
+		require.Equal(t, filepath.Join(dir, "model/model.go"), cfg.GoOutput)
+		require.Empty(t, cfg.Output)
+	})
+	t.Run("default file", func(t *testing.T) {
+		dir := t.TempDir()
+		writeConfig(t, dir, "gql-spansql.json", `{"tableCase": "lowercamel"}`)
+		writeConfig(t, dir, "gql-spansql.yml", `tableCase: uppercamel`)
+		chdir(t, dir)
+		cfg, err := loadConfig("")
+		require.NoError(t, err)
+		require.Equal(t, "uppercamel", cfg.TableCase)
+	})
+	t.Run("no default file", func(t *testing.T) {
+		chdir(t, t.TempDir())
+		cfg, err := loadConfig("")
+		require.NoError(t, err)
+		require.Equal(t, &config{}, cfg)
+	})
+	t.Run("not found", func(t *testing.T) {
+		_, err := loadConfig(filepath.Join(t.TempDir(), "gql-spansql.yaml"))
+		require.Error(t, err)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		path := writeConfig(t, t.TempDir(), "gql-spansql.json", `{"tableCase": `)
+		_, err := loadConfig(path)
+		require.ErrorContains(t, err, path)
+	})
+	t.Run("unknown key", func(t *testing.T) {
+		dir := t.TempDir()
+		_, err := loadConfig(writeConfig(t, dir, "gql-spansql.yaml", "tableCases: snake\n"))
+		require.ErrorContains(t, err, "tableCases")
+		_, err = loadConfig(writeConfig(t, dir, "gql-spansql.json", `{"tableCases": "snake"}`))
+		require.ErrorContains(t, err, "tableCases")
+	})
+	t.Run("empty", func(t *testing.T) {
+		cfg, err := loadConfig(writeConfig(t, t.TempDir(), "gql-spansql.yaml", ""))
+		require.NoError(t, err)
+		require.Equal(t, &config{}, cfg)
+	})
+}
+
+func TestConfig_Override(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "gql-spansql.yaml", `schema:
+  - schema.graphql
+tableCase: snake
+columnCase: snake
+scalars:
+  DateTime: TIMESTAMP
+  Email: STRING(256)
+emit: go
+`)
+	t.Run("defaults", func(t *testing.T) {
+		cfg, err := loadConfig(path)
+		require.NoError(t, err)
+		require.NoError(t, cfg.override())
+		require.Equal(t, "snake", cfg.TableCase)
+		require.Equal(t, "go", cfg.Emit)
+		require.Equal(t, "googlesql", cfg.Dialect)
+		require.Equal(t, "model", cfg.GoPackage)
+	})
+	// flags set here stay set in flag.CommandLine, so this must be the last.
+	t.Run("flags", func(t *testing.T) {
+		for name, value := range map[string]string{
+			"s":          "a.graphql,b.graphql",
+			"table-case": "lowercamel",
+			"scalar":     "Email=STRING(128)",
+			"exclude":    "*Payload,*Input",
+			"o":          "out.sql",
+		} {
+			require.NoError(t, flag.Set(name, value))
+		}
+		require.NoError(t, flag.Set("scalar", "Money=NUMERIC"))
+		cfg, err := loadConfig(path)
+		require.NoError(t, err)
+		require.NoError(t, cfg.override())
+		require.Equal(t, []string{"a.graphql", "b.graphql"}, cfg.Schema)
+		require.Equal(t, "lowercamel", cfg.TableCase)
+		require.Equal(t, "snake", cfg.ColumnCase)
+		require.Equal(t, map[string]string{
+			"DateTime": "TIMESTAMP",
+			"Email":    "STRING(128)",
+			"Money":    "NUMERIC",
+		}, cfg.ScalarTypes)
+		require.Equal(t, []string{"*Payload", "*Input"}, cfg.ExcludeTypes)
+		require.Equal(t, "out.sql", cfg.Output)
+		require.Equal(t, "out.sql", cfg.GoOutput)
+		require.Equal(t, "go", cfg.Emit)
+	})
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/nktks/gql-spansql/gqlspansql"
+)
+
+// printDiagnostics prints ds to w as file:line:col lines, or as a JSON array for editors.
+func printDiagnostics(w io.Writer, ds []*gqlspansql.Diagnostic, format string) error {
+	switch format {
+	case "json":
+		if ds == nil {
+			ds = []*gqlspansql.Diagnostic{}
+		}
+		return json.NewEncoder(w).Encode(ds)
+	case "text":
+		for _, d := range ds {
+			if _, err := fmt.Fprintln(w, d.String()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("diagnostics format %s not found.", format)
+}
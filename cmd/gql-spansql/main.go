@@ -10,8 +10,8 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/nktks/gql-spansql/internal/converter"
-	"github.com/vektah/gqlparser/v2"
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/nktks/gql-spansql/gqlspansql"
 	"github.com/vektah/gqlparser/v2/ast"
 )
 
@@ -31,28 +31,92 @@ func (s *fschemas) Set(value string) error {
 	return nil
 }
 
+type fscalars []string
+
+func (s *fscalars) String() string {
+	return fmt.Sprint(*s)
+}
+
+func (s *fscalars) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+type fpatterns []string
+
+func (s *fpatterns) String() string {
+	return fmt.Sprint(*s)
+}
+
+func (s *fpatterns) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		*s = append(*s, v)
+	}
+	return nil
+}
+
 var (
 	schemas     fschemas
+	scalars     fscalars
+	includes    fpatterns
+	excludes    fpatterns
+	embeddeds   fpatterns
+	jsonFields  fpatterns
 	loose       = flag.Bool("loose", false, "loose type check.")
+	strict      = flag.Bool("strict", false, "error on custom scalars without spanner type and relations to types without detectable primary key.")
 	createdName = flag.String("created-column-name", "", "if not empty, add this column as created_at Timestamp column.")
 	updatedName = flag.String("updated-column-name", "", "if not empty, add this column as updated_at Timestamp column.")
 	tableCase   = flag.String("table-case", "", "snake or lowercamel or uppercamel. if empty no convert.")
 	columnCase  = flag.String("column-case", "", "snake or lowercamel or uppercamel. if empty no convert.")
+	foreignKey  = flag.Bool("foreign-key", false, "add FOREIGN KEY constraints to relation fields.")
+	manyToMany  = flag.Bool("many-to-many", false, "convert list relation fields to join tables.")
+	relay       = flag.Bool("relay", false, "recognize Relay connection, edge, PageInfo types and Node global ids.")
+	jsonMode    = flag.Bool("json", false, "store nested lists in JSON columns.")
+	argFields   = flag.Bool("fields-with-arguments", false, "convert fields with arguments to columns. they are skipped by default.")
+	diff        = flag.String("diff", "", "path to current DDL. if not empty, print statements to migrate it to the schema.")
+	dialect     = flag.String("dialect", "googlesql", "googlesql or postgresql.")
+	emit        = flag.String("emit", "ddl", "ddl or go. go prints structs for spanner.Row.ToStruct instead of DDL.")
+	goPackage   = flag.String("go-package", "model", "package name of the structs printed with -emit go.")
+	output      = flag.String("o", "", "path to write the output. if empty, print to stdout.")
+	diagFormat  = flag.String("diagnostics", "text", "text or json. format of the errors and warnings printed to stderr.")
+	configPath  = flag.String("config", "", "path to config file. if empty, gql-spansql.yaml, gql-spansql.yml or gql-spansql.json is loaded if exists.")
 )
 
 func init() {
 	flag.Var(&schemas, "s", "comma-separated path to input schema")
+	flag.Var(&scalars, "scalar", "mapping of custom scalar to spanner type in the form of Name=TYPE, e.g. DateTime=TIMESTAMP. can be repeated.")
+	flag.Var(&includes, "include", "comma-separated glob patterns of the types converted to tables, e.g. *Model. all types if not given. can be repeated.")
+	flag.Var(&excludes, "exclude", "comma-separated glob patterns of the types not converted to tables, e.g. *Payload,*Input. can be repeated.")
+	flag.Var(&embeddeds, "embedded", "comma-separated glob patterns of the value object types flattened into the tables referring to them, e.g. Address,Money. can be repeated.")
+	flag.Var(&jsonFields, "json-fields", "comma-separated glob patterns of the fields stored in JSON columns in the form of Type.field, e.g. User.settings,*.metadata. can be repeated.")
 }
 
 func main() {
 	flag.Parse()
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := cfg.override(); err != nil {
+		log.Fatal(err)
+	}
+	// the options are validated before anything is written.
+	if cfg.Dialect != "googlesql" && cfg.Dialect != "postgresql" {
+		log.Fatalf("dialect %s not found.", cfg.Dialect)
+	}
+	if cfg.Emit != "ddl" && cfg.Emit != "go" {
+		log.Fatalf("emit %s not found.", cfg.Emit)
+	}
+	if *diagFormat != "text" && *diagFormat != "json" {
+		log.Fatalf("diagnostics format %s not found.", *diagFormat)
+	}
 	var sources []*ast.Source
-	if len(schemas) > 0 {
+	if len(cfg.Schema) > 0 {
 		var files []string
-		for _, schema := range schemas {
+		for _, schema := range cfg.Schema {
 			matches, err := filepath.Glob(schema)
 			if err != nil {
-				log.Fatalf("failed to glob schema filename %s: %w", schema, err)
+				log.Fatalf("failed to glob schema filename %s: %v", schema, err)
 			}
 			for _, m := range matches {
 				if has(files, m) {
@@ -75,29 +139,69 @@ func main() {
 		}
 		sources = append(sources, &ast.Source{Input: string(b)})
 	}
-	schema, err := loadGQL(sources)
+	schema, err := gqlspansql.LoadSchema(sources...)
 	if err != nil {
 		log.Fatal(err)
 	}
-
-	c, err := converter.NewConverter(schema, *loose, *createdName, *updatedName, *tableCase, *columnCase)
+	c, err := gqlspansql.New(schema, cfg.Options)
 	if err != nil {
 		log.Fatal(err)
 	}
-	sql, err := c.SpannerSQL()
+	err = run(c, cfg)
+	if perr := printDiagnostics(os.Stderr, c.Diagnostics(), *diagFormat); perr != nil {
+		log.Fatal(perr)
+	}
 	if err != nil {
+		var d *gqlspansql.Diagnostic
+		if errors.As(err, &d) {
+			// already printed as a diagnostic.
+			os.Exit(1)
+		}
 		log.Fatal(err)
 	}
-	fmt.Print(sql)
-
 }
 
-func loadGQL(sources []*ast.Source) (*ast.Schema, error) {
-	schema, err := gqlparser.LoadSchema(sources...)
-	if err != nil {
-		return nil, err
+// run converts the schema and writes the output configured by cfg.
+func run(c *gqlspansql.Converter, cfg *config) error {
+	if cfg.Emit == "go" && *diff == "" {
+		src, err := c.GoStructs(cfg.GoPackage)
+		if err != nil {
+			return err
+		}
+		return write(cfg.GoOutput, src)
+	}
+	var (
+		stmts []spansql.DDLStmt
+		err   error
+	)
+	if *diff != "" {
+		b, err := os.ReadFile(*diff)
+		if err != nil {
+			return fmt.Errorf("Read from file failed: %w", err)
+		}
+		ddl, err := spansql.ParseDDL(*diff, string(b))
+		if err != nil {
+			return err
+		}
+		stmts, err = c.Diff(ddl)
+		if err != nil {
+			return err
+		}
+	} else {
+		stmts, err = c.Statements()
+		if err != nil {
+			return err
+		}
+	}
+	r := gqlspansql.GoogleSQL
+	if cfg.Dialect == "postgresql" {
+		r = gqlspansql.PostgreSQL
+	}
+	var b strings.Builder
+	if err := gqlspansql.Render(&b, stmts, r); err != nil {
+		return err
 	}
-	return schema, nil
+	return write(cfg.Output, b.String())
 }
 
 func readStdin() ([]byte, error) {
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/nktks/gql-spansql/internal/reverse"
+)
+
+var (
+	ddlPath = flag.String("s", "", "path to input DDL. if empty, read from stdin.")
+)
+
+func main() {
+	flag.Parse()
+	var b []byte
+	var err error
+	if *ddlPath != "" {
+		b, err = os.ReadFile(*ddlPath)
+		if err != nil {
+			log.Fatalf("Read from file failed: %v", err)
+		}
+	} else {
+		b, err = readStdin()
+		if err != nil {
+			log.Fatalf("Read from stdin failed: %v", err)
+		}
+	}
+	ddl, err := spansql.ParseDDL(*ddlPath, string(b))
+	if err != nil {
+		log.Fatal(err)
+	}
+	gql, err := reverse.NewConverter(ddl).GraphQL()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(gql)
+}
+
+func readStdin() ([]byte, error) {
+	stat, err := os.Stdin.Stat()
+	if err != nil {
+		return []byte{}, err
+	}
+	if (stat.Mode() & os.ModeCharDevice) == 0 {
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return []byte{}, err
+		}
+		return b, nil
+	} else {
+		return []byte{}, nil
+	}
+}
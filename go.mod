@@ -3,18 +3,20 @@ module github.com/nktks/gql-spansql
 go 1.22
 
 require (
-	cloud.google.com/go/spanner v1.19.0
+	cloud.google.com/go/spanner v1.73.0
 	github.com/iancoleman/strcase v0.1.3
 	github.com/jinzhu/inflection v1.0.0
 	github.com/stretchr/testify v1.9.0
 	github.com/vektah/gqlparser/v2 v2.5.16
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	cloud.google.com/go v0.82.0 // indirect
+	cloud.google.com/go v0.116.0 // indirect
 	github.com/agnivade/levenshtein v1.1.1 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/kr/pretty v0.3.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	golang.org/x/exp v0.0.0-20240613232115-7f521ea00fb8 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	github.com/rogpeppe/go-internal v1.10.0 // indirect
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 )
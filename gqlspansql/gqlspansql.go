@@ -0,0 +1,113 @@
+// Package gqlspansql converts GraphQL schemas to Cloud Spanner DDL.
+//
+//	schema, err := gqlspansql.LoadSchema(&ast.Source{Name: "schema.graphql", Input: input})
+//	ddl, diagnostics, err := gqlspansql.Convert(schema, gqlspansql.Options{TableCase: "snake", ColumnCase: "snake"})
+package gqlspansql
+
+import (
+	"io"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/nktks/gql-spansql/internal/converter"
+	"github.com/nktks/gql-spansql/internal/postgresql"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+type (
+	// Options is the options of Converter. It can be decoded from a YAML or JSON configuration.
+	Options = converter.Options
+	// Diagnostic is an error or a warning of the conversion with the position in the schema.
+	Diagnostic = converter.Diagnostic
+	// Severity is the severity of Diagnostic.
+	Severity = converter.Severity
+)
+
+const (
+	SeverityError   = converter.SeverityError
+	SeverityWarning = converter.SeverityWarning
+)
+
+// Converter converts a GraphQL schema to statements. It also provides diffs and Go structs.
+type Converter struct {
+	c *converter.Converter
+}
+
+// New returns Converter of s configured by o.
+func New(s *ast.Schema, o Options) (*Converter, error) {
+	c, err := converter.New(s, o)
+	if err != nil {
+		return nil, err
+	}
+	return &Converter{c: c}, nil
+}
+
+// Statements converts the schema to the statements in the order to be applied.
+// They must be rendered by Render, because spansql renders FLOAT32 columns as FLOAT64.
+func (c *Converter) Statements() ([]spansql.DDLStmt, error) {
+	return c.c.Statements()
+}
+
+// Diff returns the statements which migrate current to the schema.
+// They must be rendered by Render like Statements.
+func (c *Converter) Diff(current *spansql.DDL) ([]spansql.DDLStmt, error) {
+	return c.c.Diff(current)
+}
+
+// GoStructs returns the Go source of package pkg which has a struct per table.
+func (c *Converter) GoStructs(pkg string) (string, error) {
+	return c.c.GoStructs(pkg)
+}
+
+// Diagnostics returns the errors and warnings reported so far.
+func (c *Converter) Diagnostics() []*Diagnostic {
+	return c.c.Diagnostics()
+}
+
+// Renderer renders a statement as SQL without the terminating semicolon.
+type Renderer func(spansql.DDLStmt) (string, error)
+
+// GoogleSQL renders stmt in the GoogleSQL dialect.
+func GoogleSQL(stmt spansql.DDLStmt) (string, error) {
+	return converter.GoogleSQL(stmt)
+}
+
+// PostgreSQL renders stmt in the PostgreSQL dialect of spanner.
+func PostgreSQL(stmt spansql.DDLStmt) (string, error) {
+	return postgresql.Stmt(stmt)
+}
+
+// Render writes stmts rendered by r to w, each terminated by ";\n".
+func Render(w io.Writer, stmts []spansql.DDLStmt, r Renderer) error {
+	return converter.Render(w, stmts, converter.Renderer(r))
+}
+
+// Directives returns the source declaring the directives which annotate a GraphQL schema with spanner mapping.
+// LoadSchema loads it together with the schema sources.
+func Directives() *ast.Source {
+	d := *converter.Directives
+	return &d
+}
+
+// LoadSchema loads the schema from sources together with Directives.
+func LoadSchema(sources ...*ast.Source) (*ast.Schema, error) {
+	return gqlparser.LoadSchema(append([]*ast.Source{Directives()}, sources...)...)
+}
+
+// ParseScalarTypes parses the mappings of custom scalars to spanner types in the form of Name=TYPE
+// for Options.ScalarTypes.
+func ParseScalarTypes(mappings []string) (map[string]string, error) {
+	return converter.ParseScalarTypes(mappings)
+}
+
+// Convert converts s to DDL. The diagnostics are returned even if the conversion fails,
+// and the error is one of them when it is caused by the schema.
+// It is an error if s has FLOAT32 columns, which spansql can not render. Use Converter and Render for them.
+func Convert(s *ast.Schema, o Options) (*spansql.DDL, []*Diagnostic, error) {
+	c, err := converter.New(s, o)
+	if err != nil {
+		return nil, nil, err
+	}
+	ddl, err := c.DDL()
+	return ddl, c.Diagnostics(), err
+}
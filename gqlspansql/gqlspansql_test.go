@@ -0,0 +1,99 @@
+package gqlspansql_test
+
+import (
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/nktks/gql-spansql/gqlspansql"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestConvert(t *testing.T) {
+	s, err := gqlspansql.LoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+type User {
+  userId: ID!
+  name: String! @index
+  createdAt: Time! @spannerPK
+  email: Email
+}
+
+scalar Time
+scalar Email
+`})
+	require.NoError(t, err)
+	t.Run("valid", func(t *testing.T) {
+		ddl, diagnostics, err := gqlspansql.Convert(s, gqlspansql.Options{TableCase: "snake", ColumnCase: "snake"})
+		require.NoError(t, err)
+		require.Len(t, ddl.List, 2)
+		ct, ok := ddl.List[0].(*spansql.CreateTable)
+		require.True(t, ok)
+		require.Equal(t, spansql.ID("user"), ct.Name)
+		require.Equal(t, []spansql.KeyPart{{Column: "created_at"}}, ct.PrimaryKey)
+		ci, ok := ddl.List[1].(*spansql.CreateIndex)
+		require.True(t, ok)
+		require.Equal(t, spansql.ID("user_by_name"), ci.Name)
+		require.Len(t, diagnostics, 2)
+		for _, d := range diagnostics {
+			require.Equal(t, gqlspansql.SeverityWarning, d.Severity)
+			require.Equal(t, "schema.graphql", d.File)
+		}
+	})
+	t.Run("invalid options", func(t *testing.T) {
+		_, _, err := gqlspansql.Convert(s, gqlspansql.Options{TableCase: "invalid"})
+		require.Error(t, err)
+	})
+	t.Run("error", func(t *testing.T) {
+		_, diagnostics, err := gqlspansql.Convert(s, gqlspansql.Options{Strict: true})
+		require.Error(t, err)
+		var d *gqlspansql.Diagnostic
+		require.ErrorAs(t, err, &d)
+		require.Equal(t, gqlspansql.SeverityError, d.Severity)
+		require.Contains(t, diagnostics, d)
+	})
+	t.Run("float32", func(t *testing.T) {
+		s, err := gqlspansql.LoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+type Item {
+  itemId: ID!
+  score: Float! @spannerType(type: "FLOAT32")
+}
+`})
+		require.NoError(t, err)
+		_, _, err = gqlspansql.Convert(s, gqlspansql.Options{})
+		require.ErrorContains(t, err, "FLOAT32")
+		c, err := gqlspansql.New(s, gqlspansql.Options{})
+		require.NoError(t, err)
+		stmts, err := c.Statements()
+		require.NoError(t, err)
+		var b strings.Builder
+		require.NoError(t, gqlspansql.Render(&b, stmts, gqlspansql.GoogleSQL))
+		require.Equal(t, `CREATE TABLE Item (
+  itemId STRING(MAX) NOT NULL,
+  score FLOAT32 NOT NULL,
+) PRIMARY KEY(itemId);
+`, b.String())
+	})
+	t.Run("converter", func(t *testing.T) {
+		c, err := gqlspansql.New(s, gqlspansql.Options{})
+		require.NoError(t, err)
+		stmts, err := c.Statements()
+		require.NoError(t, err)
+		var b strings.Builder
+		require.NoError(t, gqlspansql.Render(&b, stmts, gqlspansql.GoogleSQL))
+		require.Equal(t, `CREATE TABLE User (
+  userId STRING(MAX) NOT NULL,
+  name STRING(MAX) NOT NULL,
+  createdAt TIMESTAMP NOT NULL,
+  email STRING(MAX),
+) PRIMARY KEY(createdAt);
+CREATE INDEX UserByName ON User(name);
+`, b.String())
+		current, err := spansql.ParseDDL("current.sql", b.String())
+		require.NoError(t, err)
+		diff, err := c.Diff(current)
+		require.NoError(t, err)
+		require.Empty(t, diff)
+		require.NotEmpty(t, c.Diagnostics())
+	})
+}
@@ -60,7 +60,6 @@ func ConvertCase(s string, c Case) string {
 	default:
 		return s
 	}
-	return s
 }
 
 func NewCase(c string) Case {
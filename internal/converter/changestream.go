@@ -0,0 +1,77 @@
+package converter
+
+import (
+	"fmt"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// changeStreams returns CREATE CHANGE STREAM statements of @changeStream of the tables.
+// the types annotated with the same stream name are watched by one stream.
+func (c *Converter) changeStreams(tables []*spansql.CreateTable, defs map[spansql.ID]*ast.Definition) ([]*spansql.CreateChangeStream, error) {
+	var streams []*spansql.CreateChangeStream
+	byName := map[spansql.ID]*spansql.CreateChangeStream{}
+	for _, ct := range tables {
+		def := defs[ct.Name]
+		if def == nil {
+			continue
+		}
+		for _, d := range def.Directives.ForNames(changeStreamDirective) {
+			name, _ := stringArg(d, "name")
+			wd, err := c.watchDef(def, ct, stringListArg(d, "columns"))
+			if err != nil {
+				return nil, c.errorAt(def.Position, err)
+			}
+			var opts spansql.ChangeStreamOptions
+			if v, ok := stringArg(d, "retentionPeriod"); ok {
+				opts.RetentionPeriod = &v
+			}
+			if v, ok := stringArg(d, "valueCaptureType"); ok {
+				opts.ValueCaptureType = &v
+			}
+			cs, ok := byName[spansql.ID(name)]
+			if !ok {
+				cs = &spansql.CreateChangeStream{Name: spansql.ID(name), Options: opts}
+				byName[cs.Name] = cs
+				streams = append(streams, cs)
+			} else if opts != (spansql.ChangeStreamOptions{}) {
+				if cs.Options != (spansql.ChangeStreamOptions{}) && cs.Options.SQL() != opts.SQL() {
+					return nil, c.errorAt(def.Position, fmt.Errorf("options of change stream %s of %s conflict with the other types.", name, def.Name))
+				}
+				cs.Options = opts
+			}
+			for _, w := range cs.Watch {
+				if w.Table == wd.Table {
+					return nil, c.errorAt(def.Position, fmt.Errorf("change stream %s already watches %s.", name, def.Name))
+				}
+			}
+			cs.Watch = append(cs.Watch, wd)
+		}
+	}
+	return streams, nil
+}
+
+// watchDef returns the watch of ct. all columns are watched if columns is empty.
+// the primary key columns are always watched and can not be listed.
+func (c *Converter) watchDef(def *ast.Definition, ct *spansql.CreateTable, columns []string) (spansql.WatchDef, error) {
+	wd := spansql.WatchDef{Table: ct.Name, WatchAllCols: len(columns) == 0}
+	for _, ref := range columns {
+		names, err := c.columnsOf(def, ct, ref)
+		if err != nil {
+			return spansql.WatchDef{}, err
+		}
+		if names == nil {
+			return spansql.WatchDef{}, fmt.Errorf("change stream column %s of %s is not found.", ref, def.Name)
+		}
+		for _, name := range names {
+			for _, kp := range ct.PrimaryKey {
+				if kp.Column == name {
+					return spansql.WatchDef{}, fmt.Errorf("change stream column %s of %s is a primary key column, which is always watched.", ref, def.Name)
+				}
+			}
+		}
+		wd.Columns = append(wd.Columns, names...)
+	}
+	return wd, nil
+}
@@ -0,0 +1,82 @@
+package converter_test
+
+import (
+	_ "embed"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/nktks/gql-spansql/internal/converter"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/change_stream.gql
+var changeStreamBody []byte
+
+func TestConverter_ChangeStreams(t *testing.T) {
+	s, err := loadGQL(changeStreamBody)
+	require.NoError(t, err)
+	t.Run("valid", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{ExcludeTypes: []string{"InvalidColumn", "KeyColumn"}})
+		require.NoError(t, err)
+		stmts, err := c.Statements()
+		require.NoError(t, err)
+		var b strings.Builder
+		require.NoError(t, converter.Render(&b, stmts, converter.GoogleSQL))
+		require.Equal(t, `CREATE TABLE Post (
+  postId STRING(MAX) NOT NULL,
+  authorId STRING(MAX) NOT NULL,
+) PRIMARY KEY(postId);
+CREATE TABLE User (
+  userId STRING(MAX) NOT NULL,
+  name STRING(MAX) NOT NULL,
+  age INT64,
+) PRIMARY KEY(userId);
+CREATE CHANGE STREAM Everything FOR Post, User OPTIONS (retention_period='7d');
+CREATE CHANGE STREAM UserNames FOR User(name) OPTIONS (value_capture_type='NEW_VALUES');
+`, b.String())
+	})
+	t.Run("invalid column", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{ExcludeTypes: []string{"KeyColumn"}})
+		require.NoError(t, err)
+		_, err = c.Statements()
+		require.Error(t, err)
+	})
+	t.Run("key column", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{ExcludeTypes: []string{"InvalidColumn"}})
+		require.NoError(t, err)
+		_, err = c.Statements()
+		require.Error(t, err)
+	})
+}
+
+func TestConverter_DiffChangeStreams(t *testing.T) {
+	s, err := loadGQL(changeStreamBody)
+	require.NoError(t, err)
+	c, err := converter.New(s, converter.Options{ExcludeTypes: []string{"InvalidColumn", "KeyColumn"}})
+	require.NoError(t, err)
+	current, err := spansql.ParseDDL("current.sql", `
+CREATE TABLE User (
+  userId STRING(MAX) NOT NULL,
+  name STRING(MAX) NOT NULL,
+  age INT64,
+) PRIMARY KEY(userId);
+CREATE TABLE Post (
+  postId STRING(MAX) NOT NULL,
+  authorId STRING(MAX) NOT NULL,
+) PRIMARY KEY(postId);
+CREATE CHANGE STREAM Everything FOR User OPTIONS (retention_period='1d');
+CREATE CHANGE STREAM Legacy FOR ALL;
+`)
+	require.NoError(t, err)
+	stmts, err := c.Diff(current)
+	require.NoError(t, err)
+	var b strings.Builder
+	require.NoError(t, converter.Render(&b, stmts, converter.GoogleSQL))
+	require.Equal(t, `ALTER CHANGE STREAM Everything DROP FOR ALL;
+DROP CHANGE STREAM Legacy;
+ALTER CHANGE STREAM Everything SET FOR Post, User;
+ALTER CHANGE STREAM Everything SET OPTIONS (retention_period='7d');
+CREATE CHANGE STREAM UserNames FOR User(name) OPTIONS (value_capture_type='NEW_VALUES');
+`, b.String())
+}
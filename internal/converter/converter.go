@@ -8,6 +8,9 @@ import (
 	"strings"
 
 	"cloud.google.com/go/spanner/spansql"
+	"github.com/nktks/gql-spansql/internal/gostruct"
+	"github.com/nktks/gql-spansql/internal/postgresql"
+	"github.com/nktks/gql-spansql/internal/spantype"
 	"github.com/vektah/gqlparser/v2/ast"
 
 	"github.com/jinzhu/inflection"
@@ -16,67 +19,367 @@ import (
 type Converter struct {
 	schema                   *ast.Schema
 	loose                    bool
+	strict                   bool
 	createdName, updatedName string
 	tableCase, columnCase    Case
+	foreignKey               bool
+	manyToMany               bool
+	scalarTypes              map[string]spansql.Type
+	includeTypes             []string
+	excludeTypes             []string
+	fieldsWithArguments      bool
+	relay                    bool
+	interfaces               map[string]string
+	unions                   map[string]string
+	embeddedTypes            []string
+	json                     bool
+	jsonFields               []string
+	enums                    map[string]string
+	embedding                map[string]bool
+	diagnostics              []*Diagnostic
+	reported                 map[string]bool
+}
+
+// Options is the options of Converter. It can be decoded from a YAML or JSON configuration.
+type Options struct {
+	// Loose allows loose type check.
+	Loose bool `yaml:"loose" json:"loose"`
+	// Strict makes the silent fallbacks errors:
+	// custom scalars without a spanner type and relations to types whose primary key can not be detected.
+	Strict bool `yaml:"strict" json:"strict"`
+	// CreatedColumnName adds this column as created_at Timestamp column if not empty.
+	CreatedColumnName string `yaml:"createdColumnName" json:"createdColumnName"`
+	// UpdatedColumnName adds this column as updated_at Timestamp column if not empty.
+	UpdatedColumnName string `yaml:"updatedColumnName" json:"updatedColumnName"`
+	// TableCase is snake or lowercamel or uppercamel. if empty no convert.
+	TableCase string `yaml:"tableCase" json:"tableCase"`
+	// ColumnCase is snake or lowercamel or uppercamel. if empty no convert.
+	ColumnCase string `yaml:"columnCase" json:"columnCase"`
+	// ForeignKeys adds FOREIGN KEY constraints to all relation fields.
+	ForeignKeys bool `yaml:"foreignKey" json:"foreignKey"`
+	// ManyToMany converts all list relation fields to join tables.
+	ManyToMany bool `yaml:"manyToMany" json:"manyToMany"`
+	// ScalarTypes maps custom scalar names to spanner types, e.g. DateTime: TIMESTAMP or Email: STRING(256).
+	// it takes precedence over @spannerType and the description of the scalar,
+	// which take precedence over the default mappings of Time, TimeStamp, Timestamp and Date.
+	ScalarTypes map[string]string `yaml:"scalars" json:"scalars"`
+	// IncludeTypes is the glob patterns of the object types which are converted to tables, e.g. *Model.
+	// all object types are converted if empty.
+	IncludeTypes []string `yaml:"include" json:"include"`
+	// ExcludeTypes is the glob patterns of the object types which are not converted to tables, e.g. *Payload.
+	// it takes precedence over IncludeTypes.
+	ExcludeTypes []string `yaml:"exclude" json:"exclude"`
+	// FieldsWithArguments converts the fields with arguments to columns.
+	// they are skipped by default because they are resolvers rather than stored data.
+	FieldsWithArguments bool `yaml:"fieldsWithArguments" json:"fieldsWithArguments"`
+	// Relay recognizes the Relay spec: connection, edge and PageInfo types are not converted to tables,
+	// connection fields are the inverse side of one-to-many relations, and the global id of Node
+	// is not a column if the type has its own <Type>Id field.
+	Relay bool `yaml:"relay" json:"relay"`
+	// Interfaces maps interface names to how their implementing types are stored,
+	// SINGLE_TABLE or TABLE_PER_TYPE. @inheritance of the interface takes precedence over it.
+	// the interfaces which are not mapped are not converted.
+	Interfaces map[string]string `yaml:"interfaces" json:"interfaces"`
+	// Unions maps union names to how the fields referring to them are stored,
+	// DISCRIMINATOR or COLUMN_PER_TYPE. @polymorphic of the field or the union takes precedence over it.
+	// it is DISCRIMINATOR if not mapped.
+	Unions map[string]string `yaml:"unions" json:"unions"`
+	// EmbeddedTypes is the glob patterns of the object types which are value objects, e.g. Address.
+	// their fields are flattened into the prefixed columns of the tables referring to them like @embedded,
+	// and they are not converted to tables.
+	EmbeddedTypes []string `yaml:"embedded" json:"embedded"`
+	// JSON stores the nested lists, which are not allowed in ARRAY, in JSON columns.
+	JSON bool `yaml:"json" json:"json"`
+	// JSONFields is the glob patterns of the fields stored in JSON columns like @spannerJSON
+	// in the form of Type.field, e.g. User.settings or *.metadata.
+	JSONFields []string `yaml:"jsonFields" json:"jsonFields"`
+	// Enums maps enum names to how their values are stored, STRING, CHECK or INT64.
+	// CHECK is STRING with the CHECK constraint of the values, and INT64 is the numbers given by @enumValue.
+	// @spannerEnum of the enum takes precedence over it. it is STRING if not mapped.
+	Enums map[string]string `yaml:"enums" json:"enums"`
+}
+
+// Option configures optional behavior of Converter.
+type Option func(*Converter)
+
+// WithManyToMany converts all list relation fields to join tables if enabled.
+func WithManyToMany(enabled bool) Option {
+	return func(c *Converter) {
+		c.manyToMany = enabled
+	}
+}
+
+// WithForeignKeys adds FOREIGN KEY constraints to all relation fields if enabled.
+func WithForeignKeys(enabled bool) Option {
+	return func(c *Converter) {
+		c.foreignKey = enabled
+	}
 }
 
 var (
-	spanTypeRe   = regexp.MustCompile(`^SpannerType: ?(.*)$`)
-	spanColumnRe = regexp.MustCompile(`^SpannerColumn: ?(.*)$`)
+	spanTypeRe   = regexp.MustCompile(`(?m)^SpannerType: ?(.*)$`)
+	spanColumnRe = regexp.MustCompile(`(?m)^SpannerColumn: ?(.*)$`)
 )
 
-func NewConverter(s *ast.Schema, loose bool, createdName, updatedName string, tableCase, columnCase string) (*Converter, error) {
-	tc := NewCase(tableCase)
+func NewConverter(s *ast.Schema, loose bool, createdName, updatedName string, tableCase, columnCase string, opts ...Option) (*Converter, error) {
+	return New(s, Options{
+		Loose:             loose,
+		CreatedColumnName: createdName,
+		UpdatedColumnName: updatedName,
+		TableCase:         tableCase,
+		ColumnCase:        columnCase,
+	}, opts...)
+}
+
+// New returns Converter of s configured by o. opts are applied after o.
+func New(s *ast.Schema, o Options, opts ...Option) (*Converter, error) {
+	if o.Loose && o.Strict {
+		return nil, fmt.Errorf("loose and strict can not be enabled together.")
+	}
+	tc := NewCase(o.TableCase)
 	if tc == UnknownCase {
-		return nil, fmt.Errorf("table case %s not found.", tableCase)
+		return nil, fmt.Errorf("table case %s not found.", o.TableCase)
 	}
-	cc := NewCase(columnCase)
+	cc := NewCase(o.ColumnCase)
 	if cc == UnknownCase {
-		return nil, fmt.Errorf("column case %s not found.", columnCase)
-	}
-	return &Converter{
-		schema:      s,
-		loose:       loose,
-		createdName: createdName,
-		updatedName: updatedName,
-		tableCase:   tc,
-		columnCase:  cc,
-	}, nil
+		return nil, fmt.Errorf("column case %s not found.", o.ColumnCase)
+	}
+	c := &Converter{
+		schema:              s,
+		loose:               o.Loose,
+		strict:              o.Strict,
+		createdName:         o.CreatedColumnName,
+		updatedName:         o.UpdatedColumnName,
+		tableCase:           tc,
+		columnCase:          cc,
+		foreignKey:          o.ForeignKeys,
+		manyToMany:          o.ManyToMany,
+		fieldsWithArguments: o.FieldsWithArguments,
+		relay:               o.Relay,
+		scalarTypes:         map[string]spansql.Type{},
+		includeTypes:        o.IncludeTypes,
+		excludeTypes:        o.ExcludeTypes,
+		interfaces:          map[string]string{},
+		unions:              map[string]string{},
+		embeddedTypes:       o.EmbeddedTypes,
+		embedding:           map[string]bool{},
+		json:                o.JSON,
+		jsonFields:          o.JSONFields,
+		enums:               map[string]string{},
+	}
+	for name, strategy := range o.Interfaces {
+		if strategy != singleTableInheritance && strategy != tablePerTypeInheritance {
+			return nil, fmt.Errorf("inheritance strategy %s of %s not found.", strategy, name)
+		}
+		c.interfaces[name] = strategy
+	}
+	for name, strategy := range o.Unions {
+		if strategy != discriminatorPolymorphism && strategy != columnPerTypePolymorphism {
+			return nil, fmt.Errorf("polymorphism strategy %s of %s not found.", strategy, name)
+		}
+		c.unions[name] = strategy
+	}
+	for name, storage := range o.Enums {
+		if storage != stringEnum && storage != checkEnum && storage != int64Enum {
+			return nil, fmt.Errorf("enum storage %s of %s not found.", storage, name)
+		}
+		c.enums[name] = storage
+	}
+	if err := validatePatterns(o.IncludeTypes); err != nil {
+		return nil, err
+	}
+	if err := validatePatterns(o.ExcludeTypes); err != nil {
+		return nil, err
+	}
+	if err := validatePatterns(o.EmbeddedTypes); err != nil {
+		return nil, err
+	}
+	if err := validatePatterns(o.JSONFields); err != nil {
+		return nil, err
+	}
+	for name, t := range o.ScalarTypes {
+		st, err := parseSpannerType(t)
+		if err != nil {
+			return nil, fmt.Errorf("scalar type %s: %w", name, err)
+		}
+		c.scalarTypes[name] = st
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
 }
 
+// SpannerSQL converts the schema to DDL.
 func (c *Converter) SpannerSQL() (string, error) {
-	sql := ""
+	return c.render(GoogleSQL)
+}
+
+// DDL converts the schema to the statements in the order to be applied.
+// it is an error if the schema has FLOAT32 columns, because spansql renders them as FLOAT64.
+func (c *Converter) DDL() (*spansql.DDL, error) {
+	stmts, err := c.Statements()
+	if err != nil {
+		return nil, err
+	}
+	for _, stmt := range stmts {
+		if spantype.HasFloat32(stmt) {
+			return nil, fmt.Errorf("DDL can not have FLOAT32 columns because spansql renders them as FLOAT64. render Statements by GoogleSQL instead.")
+		}
+	}
+	return &spansql.DDL{List: stmts}, nil
+}
+
+// PostgreSQL converts the schema to DDL of the PostgreSQL dialect of spanner.
+func (c *Converter) PostgreSQL() (string, error) {
+	return c.render(postgresql.Stmt)
+}
+
+func (c *Converter) render(r Renderer) (string, error) {
+	stmts, err := c.Statements()
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := Render(&b, stmts, r); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// GoStructs converts the tables of the schema to Go structs of package pkg.
+func (c *Converter) GoStructs(pkg string) (string, error) {
+	stmts, err := c.Statements()
+	if err != nil {
+		return "", err
+	}
+	var tables []*spansql.CreateTable
+	for _, stmt := range stmts {
+		if ct, ok := stmt.(*spansql.CreateTable); ok {
+			tables = append(tables, ct)
+		}
+	}
+	return gostruct.Generate(pkg, tables)
+}
+
+// Statements converts the schema to the statements in the order to be applied:
+// tables followed by their indexes, foreign keys deferred by circular references, and change streams.
+func (c *Converter) Statements() ([]spansql.DDLStmt, error) {
+	var stmts []spansql.DDLStmt
 	keys := make([]string, 0, len(c.schema.Types))
 	for k := range c.schema.Types {
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
+	var names []string
 	for _, name := range keys {
 		t := c.schema.Types[name]
 		if t.BuiltIn {
 			continue
 		}
+		if strategy := c.inheritance(t); strategy != "" {
+			if strategy == singleTableInheritance && !c.excluded(t) {
+				names = append(names, name)
+			}
+			continue
+		}
+		if c.relay && t.Kind == ast.Interface && name == "Node" {
+			continue
+		}
+		// unions are the columns of the referring tables, so only interfaces without a strategy are worth a warning.
+		if t.Kind == ast.Union {
+			continue
+		}
+		if t.Kind == ast.Interface {
+			c.warnf(t.Position, "interface %s is not converted to a table.", name)
+			continue
+		}
 		if t.Kind != "OBJECT" {
 			continue
 		}
-		if name == "Query" || name == "Mutation" || name == "Subscription" {
+		if !c.isTable(t) {
 			continue
 		}
+		names = append(names, name)
+	}
+	tables := make([]*spansql.CreateTable, 0, len(names))
+	defs := map[spansql.ID]*ast.Definition{}
+	for _, name := range names {
+		t := c.schema.Types[name]
+		if t.Kind == ast.Interface {
+			st, err := c.singleTable(t)
+			if err != nil {
+				return nil, c.errorAt(t.Position, err)
+			}
+			t = st
+		}
 		s, err := c.ConvertDefinition(t)
 		if err != nil {
-			return "", err
+			return nil, c.errorAt(t.Position, err)
+		}
+		if _, ok := defs[s.Name]; ok {
+			return nil, c.errorAt(t.Position, fmt.Errorf("table %s of %s is already defined.", s.Name, name))
+		}
+		tables = append(tables, s)
+		defs[s.Name] = t
+		jts, err := c.ConvertJoinTables(t)
+		if err != nil {
+			return nil, c.errorAt(t.Position, err)
+		}
+		for _, jt := range jts {
+			if _, ok := defs[jt.Name]; ok {
+				return nil, c.errorAt(t.Position, fmt.Errorf("join table %s of %s is already defined.", jt.Name, name))
+			}
+			tables = append(tables, jt)
+			defs[jt.Name] = nil
+		}
+	}
+	tables, deferred := sortTables(tables)
+	indexNames := map[spansql.ID]string{}
+	for _, s := range tables {
+		t := defs[s.Name]
+		stmts = append(stmts, s)
+		if t == nil {
+			continue
+		}
+		indexes, err := c.convertIndexes(t, s)
+		if err != nil {
+			return nil, c.errorAt(t.Position, err)
 		}
-		sql = sql + s.SQL() + ";\n"
+		for _, ci := range indexes {
+			if other, ok := indexNames[ci.Name]; ok {
+				return nil, c.errorAt(t.Position, fmt.Errorf("index %s of %s is already defined in %s.", ci.Name, t.Name, other))
+			}
+			indexNames[ci.Name] = t.Name
+			stmts = append(stmts, ci)
+		}
+	}
+	for _, at := range deferred {
+		stmts = append(stmts, at)
+	}
+	streams, err := c.changeStreams(tables, defs)
+	if err != nil {
+		return nil, err
 	}
-	return sql, nil
+	for _, cs := range streams {
+		stmts = append(stmts, cs)
+	}
+	return stmts, nil
 }
 func (c *Converter) ConvertDefinition(def *ast.Definition) (*spansql.CreateTable, error) {
+	if err := c.checkEmbedded(def, []string{def.Name}); err != nil {
+		return nil, err
+	}
 	sc := &spansql.CreateTable{
 		Name: spansql.ID(ConvertCase(def.Name, c.tableCase)),
 	}
-	pk, found := c.DetectPK(def.Name, def.Fields)
+	if err := c.checkPK(def); err != nil {
+		return nil, err
+	}
+	pk, found := c.DetectPK(def.Name, c.fields(def))
 	sc.PrimaryKey = pk
 	if !found {
+		c.warnf(def.Position, "primary key of %s can not be detected. %s is added as the primary key.", def.Name, pk[0].Column)
 		sc.Columns = append(sc.Columns, spansql.ColumnDef{
 			Name: pk[0].Column,
 			Type: spansql.Type{
@@ -89,13 +392,20 @@ func (c *Converter) ConvertDefinition(def *ast.Definition) (*spansql.CreateTable
 	}
 	existsCreatedAt := false
 	existsUpdatedAt := false
-	for _, field := range def.Fields {
-		col, err := c.ConvertField(field)
+	for _, field := range c.fields(def) {
+		kind, err := c.relationKind(field)
 		if err != nil {
-			return nil, err
+			return nil, c.errorAt(field.Position, err)
+		}
+		if kind == manyToManyRelation {
+			continue
+		}
+		cols, err := c.ConvertFieldColumns(field)
+		if err != nil {
+			return nil, c.errorAt(field.Position, err)
 		}
 
-		sc.Columns = append(sc.Columns, *col)
+		sc.Columns = append(sc.Columns, cols...)
 		if c.createdName != "" && NormalizeCase(c.createdName) == NormalizeCase(field.Name) {
 			existsCreatedAt = true
 		}
@@ -103,6 +413,20 @@ func (c *Converter) ConvertDefinition(def *ast.Definition) (*spansql.CreateTable
 			existsUpdatedAt = true
 		}
 	}
+	if err := c.interleave(def, sc); err != nil {
+		return nil, err
+	}
+	c.checkHotspot(def, sc)
+	fks, err := c.foreignKeys(def, sc)
+	if err != nil {
+		return nil, err
+	}
+	sc.Constraints = append(sc.Constraints, fks...)
+	checks, err := c.enumChecks(def, sc)
+	if err != nil {
+		return nil, err
+	}
+	sc.Constraints = append(sc.Constraints, checks...)
 	if !existsCreatedAt && c.createdName != "" {
 		sc.Columns = append(sc.Columns, spansql.ColumnDef{
 			Name: spansql.ID(c.createdName),
@@ -126,47 +450,80 @@ func (c *Converter) ConvertDefinition(def *ast.Definition) (*spansql.CreateTable
 	return sc, nil
 }
 func (c *Converter) ConvertField(f *ast.FieldDefinition) (*spansql.ColumnDef, error) {
-	isArray := false
-	var typeBase spansql.TypeBase
+	if isJSON(f) {
+		name, err := c.ConvertFieldName(f)
+		if err != nil {
+			return nil, err
+		}
+		return &spansql.ColumnDef{
+			Name:    spansql.ID(name),
+			Type:    spansql.Type{Base: spansql.JSON},
+			NotNull: f.Type.NonNull,
+		}, nil
+	}
+	if err := c.checkFallback(f); err != nil {
+		return nil, err
+	}
+	var typ spansql.Type
 	switch f.Type.NamedType {
 	case "": // list
-		isArray = true
-		b, err := c.ConvertListField(f.Type.Elem)
+		if isNestedList(f.Type) {
+			return nil, fmt.Errorf("%s: nested list is not allowed in ARRAY. use @%s to store it as JSON.", f.Name, spannerJSONDirective)
+		}
+		if !f.Type.Elem.NonNull && !c.loose {
+			return nil, fmt.Errorf("%s: spanner is not allowed null element in ARRAY.", f.Name)
+		}
+		t, err := c.ConvertSpannerType(f.Type.Elem.NamedType)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", f.Name, err)
 		}
-		typeBase = b
+		if t.Array {
+			return nil, fmt.Errorf("%s: %s is ARRAY and ARRAY of ARRAY is not allowed.", f.Name, f.Type.Elem.NamedType)
+		}
+		typ = t
+		typ.Array = true
 	default:
-		b, err := c.ConvertType(f.Type.NamedType)
+		t, err := c.ConvertSpannerType(f.Type.NamedType)
 		if err != nil {
 			return nil, err
 		}
-		typeBase = b
+		typ = t
 	}
-	var tlen int64
-	if typeBase == spansql.String {
-		tlen = math.MaxInt64
+	if st, ok := fieldSpannerType(f); ok {
+		t, err := parseSpannerType(st)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		if typ.Array {
+			// the element type of the list field, or the type of the whole column.
+			t.Array = true
+		}
+		typ = t
 	}
 	name, err := c.ConvertFieldName(f)
 	if err != nil {
 		return nil, err
 	}
 	return &spansql.ColumnDef{
-		Name: spansql.ID(name),
-		Type: spansql.Type{
-			Array: isArray,
-			Base:  typeBase,
-			Len:   tlen,
-		},
+		Name:    spansql.ID(name),
+		Type:    typ,
 		NotNull: f.Type.NonNull,
 	}, nil
 }
 
 func (c *Converter) ConvertFieldName(f *ast.FieldDefinition) (string, error) {
+	if d := f.Directives.ForName(spannerColumnDirective); d != nil {
+		if name, ok := stringArg(d, "name"); ok && name != "" {
+			return name, nil
+		}
+	}
 	desc := f.Description
 	match := spanColumnRe.FindStringSubmatch(desc)
 	if match != nil && len(match) > 1 {
-		return match[1], nil
+		return strings.TrimSpace(match[1]), nil
+	}
+	if isJSON(f) {
+		return ConvertCase(f.Name, c.columnCase), nil
 	}
 	namedType := f.Type.NamedType
 	isArray := false
@@ -175,12 +532,8 @@ func (c *Converter) ConvertFieldName(f *ast.FieldDefinition) (string, error) {
 		namedType = f.Type.Elem.NamedType
 	}
 	if def, ok := c.schema.Types[namedType]; ok {
-		if def.Kind == "OBJECT" {
-			fieldCase := c.columnCase
-			if c.columnCase == NoConvertCase {
-				// TODO best effort..
-				fieldCase = DetectCase(f)
-			}
+		if def.Kind == "OBJECT" || c.inheritance(def) != "" {
+			fieldCase := c.relationFieldCase(f)
 			if isArray {
 				return ConvertCase(inflection.Plural(inflection.Singular(f.Name)+"Id"), fieldCase), nil
 			}
@@ -201,109 +554,221 @@ func (c *Converter) ConvertListField(l *ast.Type) (spansql.TypeBase, error) {
 }
 
 func (c *Converter) ConvertType(t string) (spansql.TypeBase, error) {
+	typ, err := c.ConvertSpannerType(t)
+	if err != nil {
+		return 0, err
+	}
+	return typ.Base, nil
+}
+
+// ConvertSpannerType converts the named GraphQL type t to the spanner type of its column,
+// including the length of STRING and BYTES.
+func (c *Converter) ConvertSpannerType(t string) (spansql.Type, error) {
+	if st, ok := c.scalarTypes[t]; ok {
+		return st, nil
+	}
 	switch t {
 	case "Int":
-		return spansql.Int64, nil
+		return spansql.Type{Base: spansql.Int64}, nil
 	case "ID", "String":
-		return spansql.String, nil
+		return spansql.Type{Base: spansql.String, Len: math.MaxInt64}, nil
 	case "Float":
-		return spansql.Float64, nil
+		return spansql.Type{Base: spansql.Float64}, nil
 	case "Boolean":
-		return spansql.Bool, nil
-	// TODO this is custom scalar type
-	case "Time", "TimeStamp", "Timestamp":
-		return spansql.Timestamp, nil
-	// TODO this is custom scalar type
-	case "Date":
-		return spansql.Date, nil
+		return spansql.Type{Base: spansql.Bool}, nil
 	default:
 		if def, ok := c.schema.Types[t]; ok {
 			if def.Kind == "ENUM" {
-				return spansql.String, nil
+				if c.enumStorage(def) == int64Enum {
+					if _, err := enumNumbers(def); err != nil {
+						return spansql.Type{}, err
+					}
+					return spansql.Type{Base: spansql.Int64}, nil
+				}
+				return spansql.Type{Base: spansql.String, Len: math.MaxInt64}, nil
 			}
 			if def.Kind == "SCALAR" {
-				desc := def.Description
-				match := spanTypeRe.FindStringSubmatch(desc)
-				if match == nil || len(match) <= 1 {
-					return spansql.String, nil
+				st, ok := scalarSpannerType(def)
+				if !ok {
+					st, ok = defaultScalarTypes[def.Name]
 				}
-				if strings.Contains(match[1], "Int") {
-					return spansql.Int64, nil
+				if !ok {
+					return spansql.Type{Base: spansql.String, Len: math.MaxInt64}, nil
 				}
-				if strings.Contains(match[1], "ID") || strings.Contains(match[1], "String") {
-					return spansql.String, nil
-				}
-				if strings.Contains(match[1], "Float") {
-					return spansql.Float64, nil
-				}
-				if strings.Contains(match[1], "Boolean") {
-					return spansql.Bool, nil
+				typ, err := parseSpannerType(st)
+				if err != nil {
+					return spansql.Type{}, fmt.Errorf("scalar type %s: %w", t, err)
 				}
+				return typ, nil
 			}
 
-			if def.Kind == "OBJECT" {
-				pk, found := c.DetectPK(def.Name, def.Fields)
+			if def.Kind == ast.Interface && c.inheritance(def) == "" {
+				return spansql.Type{}, fmt.Errorf("interface %s is not converted to a table. @%s is required to refer to it.", t, inheritanceDirective)
+			}
+			if def.Kind == "OBJECT" || def.Kind == ast.Interface {
+				parts, found := c.detectPKParts(def.Name, c.fields(def))
 				if !found {
-					return spansql.String, nil
+					return spansql.Type{Base: spansql.String, Len: math.MaxInt64}, nil
 				}
-				if len(pk) > 1 {
-					return 0, fmt.Errorf("relation to multiple pk keys is not supported. %s", t)
+				if len(parts) > 1 {
+					return spansql.Type{}, fmt.Errorf("relation to multiple pk keys is not supported. %s", t)
 				}
-				for _, f := range def.Fields {
-					if string(pk[0].Column) == f.Name {
-						col, err := c.ConvertField(f)
-						if err != nil {
-							return 0, err
-						}
-						return col.Type.Base, nil
-					}
+				col, err := c.ConvertField(parts[0].field)
+				if err != nil {
+					return spansql.Type{}, err
 				}
-				return spansql.String, nil
+				return spansql.Type{Base: col.Type.Base, Len: col.Type.Len, ProtoRef: col.Type.ProtoRef}, nil
 			}
 		}
 	}
-	return 0, fmt.Errorf("scalar type %s is not found.", t)
+	return spansql.Type{}, fmt.Errorf("scalar type %s is not found.", t)
+}
+
+// fieldSpannerType returns the spanner type annotated to the field by @spannerType,
+// or by "SpannerType:" in its description.
+func fieldSpannerType(f *ast.FieldDefinition) (string, bool) {
+	if d := f.Directives.ForName(spannerTypeDirective); d != nil {
+		return stringArg(d, "type")
+	}
+	match := spanTypeRe.FindStringSubmatch(f.Description)
+	if match == nil || len(match) <= 1 {
+		return "", false
+	}
+	return strings.TrimSpace(match[1]), true
+}
+
+// scalarSpannerType returns the spanner type annotated to the scalar by @spannerType,
+// or by "SpannerType:" in its description.
+func scalarSpannerType(def *ast.Definition) (string, bool) {
+	if d := def.Directives.ForName(spannerTypeDirective); d != nil {
+		return stringArg(d, "type")
+	}
+	match := spanTypeRe.FindStringSubmatch(def.Description)
+	if match == nil || len(match) <= 1 {
+		return "", false
+	}
+	return match[1], true
+}
+
+// typeBaseOf returns the spanner type base of the GraphQL built-in scalar t,
+// which is accepted as the spanner type for compatibility, e.g. "SpannerType: Int".
+func typeBaseOf(t string) (spansql.TypeBase, error) {
+	switch t {
+	case "Int":
+		return spansql.Int64, nil
+	case "ID", "String":
+		return spansql.String, nil
+	case "Float":
+		return spansql.Float64, nil
+	case "Boolean":
+		return spansql.Bool, nil
+	}
+	return 0, fmt.Errorf("spanner type %s is not supported.", t)
+}
+
+// relationFieldCase returns the case of the columns named after f, such as <field>Id.
+// it is the column case, or the case of f if the column case is not specified.
+func (c *Converter) relationFieldCase(f *ast.FieldDefinition) Case {
+	if c.columnCase != NoConvertCase || f == nil {
+		return c.columnCase
+	}
+	// TODO best effort..
+	return DetectCase(f)
+}
 
+type pkPart struct {
+	field *ast.FieldDefinition
+	order int64
+	desc  bool
 }
 
 func (c *Converter) DetectPK(objName string, fields ast.FieldList) ([]spansql.KeyPart, bool) {
-	kp := []spansql.KeyPart{}
-	found := false
-	for _, f := range fields {
-		desc := f.Description
-		if strings.Contains(desc, "SpannerPK") {
-			found = true
-			kp = append(kp, spansql.KeyPart{
-				Column: spansql.ID(ConvertCase(f.Name, c.columnCase)),
-			})
-			continue
+	parts, found := c.detectPKParts(objName, fields)
+	if !found {
+		var first *ast.FieldDefinition
+		if len(fields) > 0 {
+			first = fields[0]
 		}
-		if NormalizeCase(f.Name) == NormalizeCase("Id") {
-			found = true
-			kp = append(kp, spansql.KeyPart{
-				Column: spansql.ID(ConvertCase(f.Name, c.columnCase)),
-			})
-			break
+		return []spansql.KeyPart{{
+			Column: spansql.ID(ConvertCase(objName+"Id", c.relationFieldCase(first))),
+		}}, false
+	}
+	kp := []spansql.KeyPart{}
+	for _, p := range parts {
+		// a relation to the type with multiple pk keys is a part of multiple columns.
+		cols, err := c.ConvertFieldColumns(p.field)
+		if err != nil {
+			cols = []spansql.ColumnDef{{Name: spansql.ID(ConvertCase(p.field.Name, c.columnCase))}}
 		}
-		if NormalizeCase(f.Name) == NormalizeCase(objName+"Id") {
-			found = true
+		for _, col := range cols {
 			kp = append(kp, spansql.KeyPart{
-				Column: spansql.ID(ConvertCase(f.Name, c.columnCase)),
+				Column: col.Name,
+				Desc:   p.desc,
 			})
-			break
 		}
-
 	}
+	return kp, true
+}
 
+// checkPK returns an error if the primary key of def can not be the key of spanner:
+// a part which is ARRAY or JSON, nullable unless the loose mode, or has the same order as another part.
+func (c *Converter) checkPK(def *ast.Definition) error {
+	parts, found := c.detectPKParts(def.Name, c.fields(def))
 	if !found {
-		fieldCase := c.columnCase
-		if c.columnCase == NoConvertCase {
-			// TODO best effort..
-			fieldCase = DetectCase(fields[0])
+		return nil
+	}
+	orders := map[int64]string{}
+	for _, p := range parts {
+		f := p.field
+		if p.order != math.MaxInt64 {
+			if other, ok := orders[p.order]; ok {
+				return c.errorAt(f.Position, fmt.Errorf("%s: order %d of @%s is duplicated in %s.", f.Name, p.order, spannerPKDirective, other))
+			}
+			orders[p.order] = f.Name
+		}
+		if !f.Type.NonNull && !c.loose {
+			return c.errorAt(f.Position, fmt.Errorf("%s: nullable field can not be a part of the primary key of %s.", f.Name, def.Name))
+		}
+		cols, err := c.ConvertFieldColumns(f)
+		if err != nil {
+			return c.errorAt(f.Position, err)
+		}
+		for _, col := range cols {
+			if col.Type.Array || col.Type.Base == spansql.JSON {
+				return c.errorAt(f.Position, fmt.Errorf("%s: %s column can not be a part of the primary key of %s.", f.Name, spantype.SQL(col.Type), def.Name))
+			}
 		}
-		kp = append(kp, spansql.KeyPart{
-			Column: spansql.ID(ConvertCase(objName+"Id", fieldCase)),
+	}
+	return nil
+}
+
+// detectPKParts returns the fields annotated by @spannerPK or "SpannerPK" description ordered by its order argument.
+// if there is no annotated field, the first field named id or <objName>Id is the pk.
+func (c *Converter) detectPKParts(objName string, fields ast.FieldList) ([]*pkPart, bool) {
+	parts := []*pkPart{}
+	for _, f := range fields {
+		if d := f.Directives.ForName(spannerPKDirective); d != nil {
+			order, ok := intArg(d, "order")
+			if !ok {
+				order = math.MaxInt64
+			}
+			parts = append(parts, &pkPart{field: f, order: order, desc: boolArg(d, "desc")})
+			continue
+		}
+		if strings.Contains(f.Description, "SpannerPK") {
+			parts = append(parts, &pkPart{field: f, order: math.MaxInt64})
+		}
+	}
+	if len(parts) > 0 {
+		sort.SliceStable(parts, func(i, j int) bool {
+			return parts[i].order < parts[j].order
 		})
+		return parts, true
+	}
+	for _, f := range fields {
+		if NormalizeCase(f.Name) == NormalizeCase("Id") || NormalizeCase(f.Name) == NormalizeCase(objName+"Id") {
+			return []*pkPart{{field: f}}, true
+		}
 	}
-	return kp, found
+	return nil, false
 }
@@ -36,6 +36,38 @@ func TestConverter_NewConverter(t *testing.T) {
 	})
 }
 
+func TestConverter_New(t *testing.T) {
+	s, err := loadGQL(spannerSQLBody)
+	require.NoError(t, err)
+	t.Run("options", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{
+			TableCase:         "snake",
+			ColumnCase:        "snake",
+			CreatedColumnName: "created_at",
+			ScalarTypes:       map[string]string{"Time": "Int"},
+			ExcludeTypes:      []string{"Item"},
+		})
+		require.NoError(t, err)
+		sql, err := c.SpannerSQL()
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE user (
+  user_id STRING(MAX) NOT NULL,
+  state STRING(MAX) NOT NULL,
+  time INT64 NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+) PRIMARY KEY(user_id);
+`, sql)
+	})
+	t.Run("invalid scalar type", func(t *testing.T) {
+		_, err := converter.New(s, converter.Options{ScalarTypes: map[string]string{"Time": "Unknown"}})
+		require.Error(t, err)
+	})
+	t.Run("invalid case", func(t *testing.T) {
+		_, err := converter.New(s, converter.Options{TableCase: "invalid"})
+		require.Error(t, err)
+	})
+}
+
 //go:embed testdata/spanner_sql.gql
 var spannerSQLBody []byte
 
@@ -108,6 +140,44 @@ func TestConverter_ConvertDefinition(t *testing.T) {
 			require.NoError(t, err)
 			require.Equal(t, "pk", string(createTable.PrimaryKey[0].Column))
 		})
+		t.Run("has multi line description column", func(t *testing.T) {
+			c, err := converter.NewConverter(s, true, "", "", "", "")
+			require.NoError(t, err)
+			createTable, err := c.ConvertDefinition(s.Types["HasMultiLineDescription"])
+			require.NoError(t, err)
+			require.Equal(t, "pk", string(createTable.PrimaryKey[0].Column))
+		})
+		t.Run("has directive columns", func(t *testing.T) {
+			c, err := converter.NewConverter(s, true, "", "", "", "")
+			require.NoError(t, err)
+			createTable, err := c.ConvertDefinition(s.Types["HasDirective"])
+			require.NoError(t, err)
+			require.Equal(t, `CREATE TABLE HasDirective (
+  id STRING(MAX) NOT NULL,
+  createdAt TIMESTAMP NOT NULL,
+  tenant_id STRING(MAX) NOT NULL,
+) PRIMARY KEY(tenant_id, createdAt DESC, id)`, createTable.SQL())
+		})
+	})
+	t.Run("invalid pk", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{})
+		require.NoError(t, err)
+		for name, msg := range map[string]string{
+			"HasNullablePK":      "id: nullable field can not be a part of the primary key of HasNullablePK.",
+			"HasArrayPK":         "tags: ARRAY<STRING(MAX)> column can not be a part of the primary key of HasArrayPK.",
+			"HasJSONPK":          "data: JSON column can not be a part of the primary key of HasJSONPK.",
+			"HasDuplicatedOrder": "id: order 1 of @spannerPK is duplicated in tenantId.",
+		} {
+			_, err := c.ConvertDefinition(s.Types[name])
+			require.ErrorContains(t, err, msg, name)
+		}
+		t.Run("loose", func(t *testing.T) {
+			c, err := converter.New(s, converter.Options{Loose: true})
+			require.NoError(t, err)
+			createTable, err := c.ConvertDefinition(s.Types["HasNullablePK"])
+			require.NoError(t, err)
+			require.Equal(t, "id", string(createTable.PrimaryKey[0].Column))
+		})
 	})
 	t.Run("created column", func(t *testing.T) {
 		t.Run("has same name column", func(t *testing.T) {
@@ -257,6 +327,16 @@ func TestConverter_ConvertFieldName(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, "bItem", name)
 	})
+	t.Run("field has multi line description", func(t *testing.T) {
+		name, err := c.ConvertFieldName(s.Types["User"].Fields.ForName("cItem"))
+		require.NoError(t, err)
+		require.Equal(t, "c_item", name)
+	})
+	t.Run("field has directive", func(t *testing.T) {
+		name, err := c.ConvertFieldName(s.Types["User"].Fields.ForName("dItem"))
+		require.NoError(t, err)
+		require.Equal(t, "d_item", name)
+	})
 }
 
 //go:embed testdata/convert_type.gql
@@ -314,6 +394,20 @@ func TestConverter_ConvertType(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, spansql.Int64, typeBase)
 	})
+	t.Run("scalar with directive", func(t *testing.T) {
+		c, err := converter.NewConverter(s, true, "", "", "", "")
+		require.NoError(t, err)
+		typeBase, err := c.ConvertType(s.Types["User"].Fields.ForName("scalarWithDirective").Type.NamedType)
+		require.NoError(t, err)
+		require.Equal(t, spansql.Float64, typeBase)
+	})
+	t.Run("field with directive", func(t *testing.T) {
+		c, err := converter.NewConverter(s, true, "", "", "", "")
+		require.NoError(t, err)
+		column, err := c.ConvertField(s.Types["User"].Fields.ForName("fieldWithDirective"))
+		require.NoError(t, err)
+		require.Equal(t, "fieldWithDirective INT64", column.SQL())
+	})
 	t.Run("object cant detect pk", func(t *testing.T) {
 		c, err := converter.NewConverter(s, true, "", "", "", "")
 		require.NoError(t, err)
@@ -330,7 +424,7 @@ func TestConverter_ConvertType(t *testing.T) {
 	})
 }
 func loadGQL(b []byte) (*ast.Schema, error) {
-	schama, err := gqlparser.LoadSchema(&ast.Source{
+	schama, err := gqlparser.LoadSchema(converter.Directives, &ast.Source{
 		Input: string(b),
 	})
 	if err != nil {
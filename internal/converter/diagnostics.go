@@ -0,0 +1,151 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Severity is the severity of Diagnostic.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is an error or a warning of the conversion with the position in the schema.
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Column   int      `json:"column"`
+	Message  string   `json:"message"`
+}
+
+func newDiagnostic(severity Severity, pos *ast.Position, msg string) *Diagnostic {
+	d := &Diagnostic{Severity: severity, File: "-", Message: msg}
+	if pos != nil {
+		if pos.Src != nil && pos.Src.Name != "" {
+			d.File = pos.Src.Name
+		}
+		d.Line = pos.Line
+		d.Column = pos.Column
+	}
+	return d
+}
+
+// Position returns the position as file:line:col.
+func (d *Diagnostic) Position() string {
+	return fmt.Sprintf("%s:%d:%d", d.File, d.Line, d.Column)
+}
+
+// Error returns the diagnostic as file:line:col: message.
+func (d *Diagnostic) Error() string {
+	return d.Position() + ": " + d.Message
+}
+
+// String returns the diagnostic as file:line:col: severity: message.
+func (d *Diagnostic) String() string {
+	return d.Position() + ": " + string(d.Severity) + ": " + d.Message
+}
+
+// position formats p as file:line:col.
+func position(p *ast.Position) string {
+	return newDiagnostic(SeverityError, p, "").Position()
+}
+
+// Diagnostics returns the errors and warnings reported by the conversions so far.
+func (c *Converter) Diagnostics() []*Diagnostic {
+	return append([]*Diagnostic{}, c.diagnostics...)
+}
+
+func (c *Converter) report(d *Diagnostic) {
+	if c.reported == nil {
+		c.reported = map[string]bool{}
+	}
+	// the same field can be converted more than once, e.g. as a primary key referenced by relations.
+	if c.reported[d.String()] {
+		return
+	}
+	c.reported[d.String()] = true
+	c.diagnostics = append(c.diagnostics, d)
+}
+
+func (c *Converter) warnf(pos *ast.Position, format string, args ...interface{}) {
+	c.report(newDiagnostic(SeverityWarning, pos, fmt.Sprintf(format, args...)))
+}
+
+// errorAt reports err at pos and returns it as Diagnostic.
+// err which is already Diagnostic keeps its own position.
+func (c *Converter) errorAt(pos *ast.Position, err error) error {
+	var d *Diagnostic
+	if !errors.As(err, &d) {
+		d = newDiagnostic(SeverityError, pos, err.Error())
+	}
+	c.report(d)
+	return d
+}
+
+// checkFallback reports the silent fallbacks of the type of f:
+// a custom scalar which is not mapped to a spanner type, or an object whose primary key can not be detected.
+// they are warnings, or errors in the strict mode.
+func (c *Converter) checkFallback(f *ast.FieldDefinition) error {
+	if _, ok := fieldSpannerType(f); ok || isJSON(f) {
+		return nil
+	}
+	name := f.Type.Name()
+	def, ok := c.schema.Types[name]
+	if !ok || def.BuiltIn {
+		return nil
+	}
+	var msg string
+	switch def.Kind {
+	case ast.Scalar:
+		if _, ok := c.scalarTypes[name]; ok {
+			return nil
+		}
+		if _, ok := scalarSpannerType(def); ok {
+			return nil
+		}
+		if _, ok := defaultScalarTypes[name]; ok {
+			return nil
+		}
+		msg = fmt.Sprintf("%s: scalar type %s declared at %s is not mapped to a spanner type.", f.Name, name, position(def.Position))
+	case ast.Object, ast.Interface:
+		if c.inheritance(def) == "" && def.Kind == ast.Interface {
+			return nil
+		}
+		if _, found := c.detectPKParts(def.Name, c.fields(def)); found {
+			return nil
+		}
+		msg = fmt.Sprintf("%s: primary key of the relation type %s declared at %s can not be detected.", f.Name, name, position(def.Position))
+	default:
+		return nil
+	}
+	if c.strict {
+		return c.errorAt(f.Position, newDiagnostic(SeverityError, f.Position, msg))
+	}
+	c.warnf(f.Position, "%s it falls back to STRING(MAX).", msg)
+	return nil
+}
+
+// checkHotspot warns the primary key of a root table which starts with a column
+// whose values are likely monotonically increasing, which concentrates writes on a split.
+func (c *Converter) checkHotspot(def *ast.Definition, sc *spansql.CreateTable) {
+	if sc.Interleave != nil || len(sc.PrimaryKey) == 0 {
+		return
+	}
+	col := findColumn(sc.Columns, sc.PrimaryKey[0].Column)
+	if col == nil {
+		return
+	}
+	switch col.Type.Base {
+	case spansql.Timestamp:
+		c.warnf(def.Position, "primary key of %s starts with TIMESTAMP column %s, which may cause hotspots.", def.Name, col.Name)
+	case spansql.Int64:
+		c.warnf(def.Position, "primary key of %s starts with INT64 column %s, which may cause hotspots if it is sequential.", def.Name, col.Name)
+	}
+}
@@ -0,0 +1,48 @@
+package converter_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/nktks/gql-spansql/internal/converter"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+//go:embed testdata/diagnostics.gql
+var diagnosticsBody []byte
+
+func TestConverter_Diagnostics(t *testing.T) {
+	s, err := gqlparser.LoadSchema(converter.Directives, &ast.Source{Name: "diagnostics.gql", Input: string(diagnosticsBody)})
+	require.NoError(t, err)
+	t.Run("warnings", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{ExcludeTypes: []string{"Broken"}})
+		require.NoError(t, err)
+		_, err = c.SpannerSQL()
+		require.NoError(t, err)
+		var ds []string
+		for _, d := range c.Diagnostics() {
+			ds = append(ds, d.String())
+		}
+		require.Equal(t, []string{
+			"diagnostics.gql:20:11: warning: interface Node is not converted to a table.",
+			"diagnostics.gql:8:3: warning: label: scalar type Label declared at diagnostics.gql:25:8 is not mapped to a spanner type. it falls back to STRING(MAX).",
+			"diagnostics.gql:6:6: warning: primary key of Counter starts with INT64 column id, which may cause hotspots if it is sequential.",
+			"diagnostics.gql:1:6: warning: primary key of Event starts with TIMESTAMP column createdAt, which may cause hotspots.",
+			"diagnostics.gql:11:6: warning: primary key of Log can not be detected. logId is added as the primary key.",
+		}, ds)
+	})
+	t.Run("error", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{})
+		require.NoError(t, err)
+		_, err = c.SpannerSQL()
+		var d *converter.Diagnostic
+		require.ErrorAs(t, err, &d)
+		require.Equal(t, converter.SeverityError, d.Severity)
+		require.Equal(t, "diagnostics.gql", d.File)
+		require.Equal(t, 17, d.Line)
+		require.Equal(t, 3, d.Column)
+		require.Contains(t, c.Diagnostics(), d)
+	})
+}
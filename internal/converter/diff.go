@@ -0,0 +1,297 @@
+package converter
+
+import (
+	"fmt"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/nktks/gql-spansql/internal/spantype"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// schemaState is the tables, constraints, indexes and change streams of a DDL.
+type schemaState struct {
+	tables      map[spansql.ID]*spansql.CreateTable
+	tableOrder  []spansql.ID
+	constraints map[spansql.ID][]spansql.TableConstraint
+	indexes     map[spansql.ID]*spansql.CreateIndex
+	indexOrder  []spansql.ID
+	streams     map[spansql.ID]*spansql.CreateChangeStream
+	streamOrder []spansql.ID
+}
+
+func newSchemaState(stmts []spansql.DDLStmt) (*schemaState, error) {
+	s := &schemaState{
+		tables:      map[spansql.ID]*spansql.CreateTable{},
+		constraints: map[spansql.ID][]spansql.TableConstraint{},
+		indexes:     map[spansql.ID]*spansql.CreateIndex{},
+		streams:     map[spansql.ID]*spansql.CreateChangeStream{},
+	}
+	for _, stmt := range stmts {
+		switch st := stmt.(type) {
+		case *spansql.CreateTable:
+			ct := *st
+			ct.Columns = make([]spansql.ColumnDef, len(st.Columns))
+			for i, cd := range st.Columns {
+				t, err := spantype.Normalize(cd.Type)
+				if err != nil {
+					return nil, fmt.Errorf("column %s of table %s: %w", cd.Name, ct.Name, err)
+				}
+				cd.Type = t
+				ct.Columns[i] = cd
+			}
+			ct.Constraints = nil
+			for _, tc := range st.Constraints {
+				if alterable(tc) {
+					s.constraints[ct.Name] = append(s.constraints[ct.Name], tc)
+					continue
+				}
+				ct.Constraints = append(ct.Constraints, tc)
+			}
+			s.tables[ct.Name] = &ct
+			s.tableOrder = append(s.tableOrder, ct.Name)
+		case *spansql.AlterTable:
+			if ac, ok := st.Alteration.(spansql.AddConstraint); ok && alterable(ac.Constraint) {
+				s.constraints[st.Name] = append(s.constraints[st.Name], ac.Constraint)
+			}
+		case *spansql.CreateIndex:
+			s.indexes[st.Name] = st
+			s.indexOrder = append(s.indexOrder, st.Name)
+		case *spansql.CreateChangeStream:
+			s.streams[st.Name] = st
+			s.streamOrder = append(s.streamOrder, st.Name)
+		}
+	}
+	return s, nil
+}
+
+// alterable reports whether tc is a foreign key or a check constraint, which can be added to and dropped from an existing table.
+func alterable(tc spansql.TableConstraint) bool {
+	switch tc.Constraint.(type) {
+	case spansql.ForeignKey, spansql.Check:
+		return true
+	}
+	return false
+}
+
+func constraintOf(tcs []spansql.TableConstraint, tc spansql.TableConstraint) (spansql.TableConstraint, bool) {
+	for _, c := range tcs {
+		if tc.Name != "" && c.Name == tc.Name {
+			return c, true
+		}
+		if tc.Name == "" && c.SQL() == tc.SQL() {
+			return c, true
+		}
+	}
+	return spansql.TableConstraint{}, false
+}
+
+func watchSQL(cs *spansql.CreateChangeStream) string {
+	return spansql.AlterWatch{WatchAllTables: cs.WatchAllTables, Watch: cs.Watch}.SQL()
+}
+
+func keySQL(ct *spansql.CreateTable) string {
+	sql := ""
+	for _, kp := range ct.PrimaryKey {
+		sql += kp.SQL() + ","
+	}
+	if ct.Interleave != nil {
+		sql += "INTERLEAVE IN " + ct.Interleave.Parent.SQL() + " ON DELETE " + ct.Interleave.OnDelete.SQL()
+	}
+	return sql
+}
+
+// Diff returns the statements to migrate the current schema to the schema converted from GraphQL.
+// statements are ordered to be applied safely:
+// indexes and constraints are dropped before the columns and tables they depend on,
+// and tables are created before the columns, constraints and indexes which depend on them.
+// change streams stop watching before anything is dropped, and watch again after everything is created.
+// the change of the primary key or the interleave of an existing table is an error
+// because spanner can not alter them.
+func (c *Converter) Diff(current *spansql.DDL) ([]spansql.DDLStmt, error) {
+	desired, err := c.Statements()
+	if err != nil {
+		return nil, err
+	}
+	from, err := newSchemaState(current.List)
+	if err != nil {
+		return nil, err
+	}
+	to, err := newSchemaState(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		dropStreams, dropIndexes, dropConstraints, dropColumns, dropTables []spansql.DDLStmt
+		createTables, alterColumns, addConstraints, addIndexes, addStreams []spansql.DDLStmt
+	)
+
+	for _, name := range from.streamOrder {
+		cs := from.streams[name]
+		ts, ok := to.streams[name]
+		if !ok {
+			dropStreams = append(dropStreams, &spansql.DropChangeStream{Name: name})
+			continue
+		}
+		if watchSQL(cs) != watchSQL(ts) {
+			dropStreams = append(dropStreams, &spansql.AlterChangeStream{Name: name, Alteration: spansql.DropChangeStreamWatch{}})
+			addStreams = append(addStreams, &spansql.AlterChangeStream{
+				Name:       name,
+				Alteration: spansql.AlterWatch{WatchAllTables: ts.WatchAllTables, Watch: ts.Watch},
+			})
+		}
+		if ts.Options != (spansql.ChangeStreamOptions{}) && ts.Options.SQL() != cs.Options.SQL() {
+			addStreams = append(addStreams, &spansql.AlterChangeStream{
+				Name:       name,
+				Alteration: spansql.AlterChangeStreamOptions{Options: ts.Options},
+			})
+		}
+	}
+	for _, name := range to.streamOrder {
+		if _, ok := from.streams[name]; !ok {
+			addStreams = append(addStreams, to.streams[name])
+		}
+	}
+
+	for _, name := range from.indexOrder {
+		ci := from.indexes[name]
+		if ti, ok := to.indexes[name]; ok && ti.SQL() == ci.SQL() {
+			continue
+		}
+		dropIndexes = append(dropIndexes, &spansql.DropIndex{Name: name})
+	}
+	for _, name := range to.indexOrder {
+		ti := to.indexes[name]
+		if ci, ok := from.indexes[name]; ok && ti.SQL() == ci.SQL() {
+			continue
+		}
+		addIndexes = append(addIndexes, ti)
+	}
+
+	for _, table := range from.tableOrder {
+		_, keep := to.tables[table]
+		for _, tc := range from.constraints[table] {
+			if ttc, ok := constraintOf(to.constraints[table], tc); keep && ok && ttc.SQL() == tc.SQL() {
+				continue
+			}
+			if tc.Name == "" {
+				return nil, fmt.Errorf("constraint without name of table %s can not be dropped.", table)
+			}
+			dropConstraints = append(dropConstraints, &spansql.AlterTable{
+				Name:       table,
+				Alteration: spansql.DropConstraint{Name: tc.Name},
+			})
+		}
+	}
+
+	var dropped []*spansql.CreateTable
+	for _, table := range from.tableOrder {
+		ct := from.tables[table]
+		tt, ok := to.tables[table]
+		if !ok {
+			dropped = append(dropped, ct)
+			continue
+		}
+		if keySQL(ct) != keySQL(tt) {
+			return nil, fmt.Errorf("primary key or interleave of table %s can not be altered.", table)
+		}
+		for _, col := range ct.Columns {
+			if findColumn(tt.Columns, col.Name) == nil {
+				dropColumns = append(dropColumns, &spansql.AlterTable{
+					Name:       table,
+					Alteration: spansql.DropColumn{Name: col.Name},
+				})
+			}
+		}
+		for _, col := range tt.Columns {
+			cur := findColumn(ct.Columns, col.Name)
+			if cur == nil {
+				// spanner can not add a NOT NULL column to an existing table without a default,
+				// so it is added as nullable, and the next diff after the rows are backfilled alters it to NOT NULL.
+				add := col
+				add.NotNull = false
+				alterColumns = append(alterColumns, &spansql.AlterTable{
+					Name:       table,
+					Alteration: spansql.AddColumn{Def: add},
+				})
+				if col.NotNull {
+					c.warnf(c.columnPosition(table, col.Name), "%s of %s is added as nullable. backfill existing rows and diff again to alter it to NOT NULL.", col.Name, table)
+				}
+				continue
+			}
+			if spantype.SQL(cur.Type) != spantype.SQL(col.Type) || cur.NotNull != col.NotNull {
+				alterColumns = append(alterColumns, &spansql.AlterTable{
+					Name: table,
+					Alteration: spansql.AlterColumn{
+						Name:       col.Name,
+						Alteration: spansql.SetColumnType{Type: col.Type, NotNull: col.NotNull},
+					},
+				})
+			}
+		}
+	}
+	// children must be dropped before their interleave parents.
+	dropped, _ = sortTables(dropped)
+	for i := len(dropped) - 1; i >= 0; i-- {
+		dropTables = append(dropTables, &spansql.DropTable{Name: dropped[i].Name})
+	}
+
+	var created []*spansql.CreateTable
+	for _, table := range to.tableOrder {
+		if _, ok := from.tables[table]; ok {
+			continue
+		}
+		ct := *to.tables[table]
+		ct.Constraints = append(ct.Constraints, to.constraints[table]...)
+		created = append(created, &ct)
+	}
+	created, deferred := sortTables(created)
+	for _, ct := range created {
+		createTables = append(createTables, ct)
+	}
+	for _, table := range to.tableOrder {
+		_, exists := from.tables[table]
+		if !exists {
+			continue
+		}
+		for _, tc := range to.constraints[table] {
+			if ctc, ok := constraintOf(from.constraints[table], tc); ok && ctc.SQL() == tc.SQL() {
+				continue
+			}
+			addConstraints = append(addConstraints, &spansql.AlterTable{
+				Name:       table,
+				Alteration: spansql.AddConstraint{Constraint: tc},
+			})
+		}
+	}
+	for _, at := range deferred {
+		addConstraints = append(addConstraints, at)
+	}
+
+	var stmts []spansql.DDLStmt
+	for _, ss := range [][]spansql.DDLStmt{
+		dropStreams, dropIndexes, dropConstraints, dropColumns, dropTables,
+		createTables, alterColumns, addConstraints, addIndexes, addStreams,
+	} {
+		stmts = append(stmts, ss...)
+	}
+	return stmts, nil
+}
+
+// columnPosition returns the position of the field converted to column of table, or of the type if it is not found.
+// it is nil for the join tables, which have no type.
+func (c *Converter) columnPosition(table, column spansql.ID) *ast.Position {
+	for _, def := range c.schema.Types {
+		if def.BuiltIn || (def.Kind != ast.Object && def.Kind != ast.Interface) || spansql.ID(ConvertCase(def.Name, c.tableCase)) != table {
+			continue
+		}
+		for _, f := range c.fields(def) {
+			cols, err := c.ConvertFieldColumns(f)
+			if err == nil && findColumn(cols, column) != nil {
+				return f.Position
+			}
+		}
+		return def.Position
+	}
+	return nil
+}
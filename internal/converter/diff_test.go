@@ -0,0 +1,113 @@
+package converter_test
+
+import (
+	_ "embed"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/nktks/gql-spansql/internal/converter"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/diff.gql
+var diffBody []byte
+
+//go:embed testdata/diff.sql
+var diffCurrentBody []byte
+
+func TestConverter_Diff(t *testing.T) {
+	s, err := loadGQL(diffBody)
+	require.NoError(t, err)
+	c, err := converter.NewConverter(s, true, "", "", "", "")
+	require.NoError(t, err)
+	t.Run("migration", func(t *testing.T) {
+		current, err := spansql.ParseDDL("diff.sql", string(diffCurrentBody))
+		require.NoError(t, err)
+		stmts, err := c.Diff(current)
+		require.NoError(t, err)
+		var sqls []string
+		for _, stmt := range stmts {
+			sqls = append(sqls, stmt.SQL())
+		}
+		require.Equal(t, strings.Join([]string{
+			"DROP INDEX UserByEmail",
+			"ALTER TABLE User DROP CONSTRAINT FK_User_legacy",
+			"ALTER TABLE User DROP COLUMN email",
+			"ALTER TABLE User DROP COLUMN legacyId",
+			"DROP TABLE LegacyChild",
+			"DROP TABLE Legacy",
+			`CREATE TABLE Post (
+  userId STRING(MAX) NOT NULL,
+  postId STRING(MAX) NOT NULL,
+  title STRING(MAX) NOT NULL,
+) PRIMARY KEY(userId, postId),
+  INTERLEAVE IN PARENT User ON DELETE CASCADE`,
+			`CREATE TABLE Comment (
+  userId STRING(MAX) NOT NULL,
+  postId STRING(MAX) NOT NULL,
+  commentId STRING(MAX) NOT NULL,
+) PRIMARY KEY(userId, postId, commentId),
+  INTERLEAVE IN PARENT Post ON DELETE CASCADE`,
+			"ALTER TABLE User ALTER COLUMN name STRING(MAX) NOT NULL",
+			"ALTER TABLE User ADD COLUMN age INT64",
+			"ALTER TABLE User ADD COLUMN nickname STRING(MAX)",
+			"ALTER TABLE User ADD CONSTRAINT FK_User_team FOREIGN KEY (teamId) REFERENCES Team (teamId) ON DELETE NO ACTION",
+		}, "\n"), strings.Join(sqls, "\n"))
+		var ds []string
+		for _, d := range c.Diagnostics() {
+			ds = append(ds, d.String())
+		}
+		require.Contains(t, ds, "-:5:3: warning: nickname of User is added as nullable. backfill existing rows and diff again to alter it to NOT NULL.")
+	})
+	t.Run("no change", func(t *testing.T) {
+		sql, err := c.SpannerSQL()
+		require.NoError(t, err)
+		current, err := spansql.ParseDDL("current.sql", sql)
+		require.NoError(t, err)
+		stmts, err := c.Diff(current)
+		require.NoError(t, err)
+		require.Empty(t, stmts)
+	})
+	t.Run("float32", func(t *testing.T) {
+		s, err := loadGQL([]byte(`
+type Item {
+  itemId: ID!
+  score: Float @spannerType(type: "FLOAT32")
+  ratio: Float @spannerType(type: "FLOAT32")
+}
+`))
+		require.NoError(t, err)
+		c, err := converter.New(s, converter.Options{})
+		require.NoError(t, err)
+		current, err := spansql.ParseDDL("current.sql", `CREATE TABLE Item (
+  itemId STRING(MAX) NOT NULL,
+  score FLOAT32,
+  ratio FLOAT64,
+) PRIMARY KEY(itemId);`)
+		require.NoError(t, err)
+		stmts, err := c.Diff(current)
+		require.NoError(t, err)
+		require.Len(t, stmts, 1)
+		sql, err := converter.GoogleSQL(stmts[0])
+		require.NoError(t, err)
+		require.Equal(t, "ALTER TABLE Item ALTER COLUMN ratio FLOAT32", sql)
+
+		// spansql parses NOT NULL after FLOAT32 as a part of the proto name.
+		current, err = spansql.ParseDDL("current.sql", `CREATE TABLE Item (
+  itemId STRING(MAX) NOT NULL,
+  score FLOAT32 NOT NULL,
+) PRIMARY KEY(itemId);`)
+		require.NoError(t, err)
+		_, err = c.Diff(current)
+		require.ErrorContains(t, err, "column score of table Item")
+	})
+	t.Run("primary key changed", func(t *testing.T) {
+		current, err := spansql.ParseDDL("current.sql", `CREATE TABLE Team (
+  id STRING(MAX) NOT NULL,
+) PRIMARY KEY(id);`)
+		require.NoError(t, err)
+		_, err = c.Diff(current)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,126 @@
+package converter
+
+import (
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Directives declares the directives which annotate a GraphQL schema with spanner mapping.
+// It must be loaded together with the schema sources.
+var Directives = &ast.Source{
+	Name: "gql-spansql/directives.graphql",
+	Input: `directive @spannerPK(order: Int, desc: Boolean = false) on FIELD_DEFINITION
+directive @spannerColumn(name: String!) on FIELD_DEFINITION
+directive @spannerType(type: String!) on FIELD_DEFINITION | SCALAR
+directive @interleave(in: String!, onDelete: SpannerOnDelete = NO_ACTION) on OBJECT
+directive @index(name: String, columns: [String!], unique: Boolean = false, nullFiltered: Boolean = false, storing: [String!], interleaveIn: String) repeatable on FIELD_DEFINITION | OBJECT
+directive @unique(name: String, columns: [String!], nullFiltered: Boolean = false, storing: [String!], interleaveIn: String) on FIELD_DEFINITION
+directive @foreignKey(name: String, onDelete: SpannerOnDelete = NO_ACTION, disable: Boolean = false) on FIELD_DEFINITION
+directive @relation(kind: SpannerRelationKind!, table: String, interleave: Boolean = false) on FIELD_DEFINITION
+directive @spannerIgnore on FIELD_DEFINITION | OBJECT
+directive @computed on FIELD_DEFINITION
+directive @inheritance(strategy: SpannerInheritance!, discriminator: String) on INTERFACE
+directive @polymorphic(strategy: SpannerPolymorphism!) on FIELD_DEFINITION | UNION
+directive @embedded on OBJECT | FIELD_DEFINITION
+directive @spannerJSON on FIELD_DEFINITION | OBJECT
+directive @spannerEnum(storage: SpannerEnumStorage!) on ENUM
+directive @enumValue(number: Int!) on ENUM_VALUE
+directive @changeStream(name: String!, columns: [String!], retentionPeriod: String, valueCaptureType: String) repeatable on OBJECT
+
+enum SpannerOnDelete {
+  CASCADE
+  NO_ACTION
+}
+
+enum SpannerRelationKind {
+  ARRAY
+  MANY_TO_MANY
+}
+
+enum SpannerInheritance {
+  SINGLE_TABLE
+  TABLE_PER_TYPE
+}
+
+enum SpannerPolymorphism {
+  DISCRIMINATOR
+  COLUMN_PER_TYPE
+}
+
+enum SpannerEnumStorage {
+  STRING
+  CHECK
+  INT64
+}
+`,
+	BuiltIn: true,
+}
+
+const (
+	spannerPKDirective     = "spannerPK"
+	spannerColumnDirective = "spannerColumn"
+	spannerTypeDirective   = "spannerType"
+	interleaveDirective    = "interleave"
+	indexDirective         = "index"
+	uniqueDirective        = "unique"
+	foreignKeyDirective    = "foreignKey"
+	relationDirective      = "relation"
+	changeStreamDirective  = "changeStream"
+	spannerIgnoreDirective = "spannerIgnore"
+	computedDirective      = "computed"
+	inheritanceDirective   = "inheritance"
+	polymorphicDirective   = "polymorphic"
+	embeddedDirective      = "embedded"
+	spannerJSONDirective   = "spannerJSON"
+	spannerEnumDirective   = "spannerEnum"
+	enumValueDirective     = "enumValue"
+)
+
+// directiveArgs returns the arguments of d, including defaults of its definition.
+func directiveArgs(d *ast.Directive) map[string]interface{} {
+	if d.Definition != nil {
+		return d.ArgumentMap(nil)
+	}
+	args := map[string]interface{}{}
+	for _, a := range d.Arguments {
+		v, err := a.Value.Value(nil)
+		if err != nil {
+			continue
+		}
+		args[a.Name] = v
+	}
+	return args
+}
+
+func stringArg(d *ast.Directive, name string) (string, bool) {
+	v, ok := directiveArgs(d)[name].(string)
+	return v, ok
+}
+
+func intArg(d *ast.Directive, name string) (int64, bool) {
+	v, ok := directiveArgs(d)[name].(int64)
+	return v, ok
+}
+
+func stringListArg(d *ast.Directive, name string) []string {
+	vs, _ := directiveArgs(d)[name].([]interface{})
+	var ss []string
+	for _, v := range vs {
+		if s, ok := v.(string); ok {
+			ss = append(ss, s)
+		}
+	}
+	return ss
+}
+
+func onDeleteArg(d *ast.Directive) spansql.OnDelete {
+	if v, _ := stringArg(d, "onDelete"); v == "CASCADE" {
+		return spansql.CascadeOnDelete
+	}
+	return spansql.NoActionOnDelete
+}
+
+func boolArg(d *ast.Directive, name string) bool {
+	v, _ := directiveArgs(d)[name].(bool)
+	return v
+}
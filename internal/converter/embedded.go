@@ -0,0 +1,125 @@
+package converter
+
+import (
+	"fmt"
+
+	"github.com/iancoleman/strcase"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// isEmbeddedType reports whether the object def is a value object embedded in the tables referring to it,
+// by @embedded of the type or Options.EmbeddedTypes.
+func (c *Converter) isEmbeddedType(def *ast.Definition) bool {
+	if def.Kind != ast.Object {
+		return false
+	}
+	return def.Directives.ForName(embeddedDirective) != nil || matchAny(c.embeddedTypes, def.Name)
+}
+
+// embeddedOf returns the object which f embeds, or nil if f is not embedded.
+func (c *Converter) embeddedOf(f *ast.FieldDefinition) *ast.Definition {
+	if isJSON(f) {
+		return nil
+	}
+	def, ok := c.schema.Types[f.Type.Name()]
+	if !ok || def.Kind != ast.Object {
+		return nil
+	}
+	if f.Directives.ForName(embeddedDirective) != nil || c.isEmbeddedType(def) {
+		return def
+	}
+	return nil
+}
+
+// storedInline reports whether def is not converted to a table because it is stored in the tables referring to it:
+// it is an embedded or JSON type, or all the fields referring to it are embedded or stored in JSON columns.
+func (c *Converter) storedInline(def *ast.Definition) bool {
+	if c.isEmbeddedType(def) || def.Directives.ForName(spannerJSONDirective) != nil {
+		return true
+	}
+	inline := false
+	for _, t := range c.schema.Types {
+		if t.BuiltIn || (t.Kind != ast.Object && t.Kind != ast.Interface) || c.excluded(t) {
+			continue
+		}
+		for _, f := range t.Fields {
+			if f.Type.Name() != def.Name {
+				continue
+			}
+			if f.Directives.ForName(embeddedDirective) == nil && !c.jsonField(t, f) {
+				return false
+			}
+			inline = true
+		}
+	}
+	return inline
+}
+
+// embeddedFields returns the fields of the embedded object def flattened into the owner of f:
+// they are prefixed by the name of f, and are nullable if f is nullable.
+// the fields embedded in def are flattened recursively.
+func (c *Converter) embeddedFields(f *ast.FieldDefinition, def *ast.Definition) ast.FieldList {
+	if c.embedding[def.Name] {
+		// recursive embedding is reported by checkEmbedded.
+		return nil
+	}
+	c.embedding[def.Name] = true
+	defer delete(c.embedding, def.Name)
+	var fields ast.FieldList
+	for _, inner := range c.fields(def) {
+		field := *inner
+		field.Name = f.Name + strcase.ToCamel(inner.Name)
+		if DetectCase(f) == SnakeCase {
+			field.Name = f.Name + "_" + inner.Name
+		}
+		if !f.Type.NonNull {
+			nullable := *inner.Type
+			nullable.NonNull = false
+			field.Type = &nullable
+		}
+		// the column name and keys of the value object do not apply to the owner.
+		field.Directives = nil
+		for _, d := range inner.Directives {
+			if d.Name == spannerTypeDirective || d.Name == spannerJSONDirective {
+				field.Directives = append(field.Directives, d)
+			}
+		}
+		fields = append(fields, &field)
+	}
+	return fields
+}
+
+// checkEmbedded returns an error if the fields of def embed objects in the unsupported way:
+// a list of them, themselves recursively, or with the directives which can not apply to the flattened fields.
+func (c *Converter) checkEmbedded(def *ast.Definition, path []string) error {
+	for _, f := range def.Fields {
+		ref := c.embeddedOf(f)
+		if ref == nil || isIgnored(f) || c.jsonField(def, f) {
+			continue
+		}
+		if f.Type.Elem != nil {
+			return fmt.Errorf("%s: list of embedded type %s is not supported. use @%s to store it as JSON.", f.Name, ref.Name, spannerJSONDirective)
+		}
+		for _, name := range []string{spannerPKDirective, indexDirective, uniqueDirective, foreignKeyDirective} {
+			if f.Directives.ForName(name) != nil {
+				return fmt.Errorf("%s: @%s can not be applied to embedded type %s, which is flattened into multiple columns.", f.Name, name, ref.Name)
+			}
+		}
+		if has(path, ref.Name) {
+			return fmt.Errorf("%s: embedded type %s is recursive.", f.Name, ref.Name)
+		}
+		if err := c.checkEmbedded(ref, append(path, ref.Name)); err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+func has(ss []string, e string) bool {
+	for _, s := range ss {
+		if s == e {
+			return true
+		}
+	}
+	return false
+}
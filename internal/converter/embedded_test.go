@@ -0,0 +1,72 @@
+package converter_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/nktks/gql-spansql/internal/converter"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/embedded.gql
+var embeddedBody []byte
+
+func TestConverter_Embedded(t *testing.T) {
+	s, err := loadGQL(embeddedBody)
+	require.NoError(t, err)
+	t.Run("flatten", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{ColumnCase: "snake", EmbeddedTypes: []string{"Geo*"}})
+		require.NoError(t, err)
+		sql, err := c.SpannerSQL()
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE User (
+  user_id STRING(MAX) NOT NULL,
+  address_street STRING(MAX) NOT NULL,
+  address_city STRING(MAX) NOT NULL,
+  address_geo_lat FLOAT64,
+  address_geo_lng FLOAT64,
+  billing_address_street STRING(MAX),
+  billing_address_city STRING(MAX),
+  billing_address_geo_lat FLOAT64,
+  billing_address_geo_lng FLOAT64,
+  balance_amount INT64,
+  balance_currency STRING(3),
+) PRIMARY KEY(user_id);
+`, sql)
+		require.Empty(t, c.Diagnostics())
+	})
+	t.Run("recursive", func(t *testing.T) {
+		s, err := loadGQL([]byte(`type Category @embedded {
+  name: String!
+  parent: Category
+}
+
+type Item {
+  itemId: ID!
+  category: Category!
+}
+`))
+		require.NoError(t, err)
+		c, err := converter.New(s, converter.Options{})
+		require.NoError(t, err)
+		_, err = c.SpannerSQL()
+		require.EqualError(t, err, "-:6:6: category: parent: embedded type Category is recursive.")
+	})
+	t.Run("directives", func(t *testing.T) {
+		s, err := loadGQL([]byte(`type Address @embedded {
+  street: String!
+  city: String!
+}
+
+type User {
+  userId: ID!
+  address: Address! @index
+}
+`))
+		require.NoError(t, err)
+		c, err := converter.New(s, converter.Options{})
+		require.NoError(t, err)
+		_, err = c.SpannerSQL()
+		require.EqualError(t, err, "-:6:6: address: @index can not be applied to embedded type Address, which is flattened into multiple columns.")
+	})
+}
@@ -0,0 +1,83 @@
+package converter
+
+import (
+	"fmt"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const (
+	stringEnum = "STRING"
+	checkEnum  = "CHECK"
+	int64Enum  = "INT64"
+)
+
+// enumStorage returns how the values of the enum def are stored, by @spannerEnum or Options.Enums.
+// it is STRING by default.
+func (c *Converter) enumStorage(def *ast.Definition) string {
+	if d := def.Directives.ForName(spannerEnumDirective); d != nil {
+		if s, ok := stringArg(d, "storage"); ok {
+			return s
+		}
+	}
+	if s, ok := c.enums[def.Name]; ok {
+		return s
+	}
+	return stringEnum
+}
+
+// enumNumbers returns the numbers of the values of the enum def given by @enumValue.
+// every value must have the unique number, so that the stored values do not depend on the order of the values.
+func enumNumbers(def *ast.Definition) (map[string]int64, error) {
+	numbers := map[string]int64{}
+	values := map[int64]string{}
+	for _, v := range def.EnumValues {
+		d := v.Directives.ForName(enumValueDirective)
+		if d == nil {
+			return nil, fmt.Errorf("value %s of enum %s stored as INT64 has no @%s.", v.Name, def.Name, enumValueDirective)
+		}
+		n, ok := intArg(d, "number")
+		if !ok {
+			return nil, fmt.Errorf("value %s of enum %s stored as INT64 has no number.", v.Name, def.Name)
+		}
+		if other, ok := values[n]; ok {
+			return nil, fmt.Errorf("number %d of enum %s is duplicated in %s and %s.", n, def.Name, other, v.Name)
+		}
+		values[n] = v.Name
+		numbers[v.Name] = n
+	}
+	return numbers, nil
+}
+
+// enumChecks returns CHECK constraints of the enum columns of def stored with CHECK,
+// which allow only the values of the enum.
+func (c *Converter) enumChecks(def *ast.Definition, sc *spansql.CreateTable) ([]spansql.TableConstraint, error) {
+	var constraints []spansql.TableConstraint
+	for _, f := range c.fields(def) {
+		enum, ok := c.schema.Types[f.Type.Name()]
+		if !ok || enum.Kind != ast.Enum || c.enumStorage(enum) != checkEnum || isJSON(f) {
+			continue
+		}
+		if _, ok := fieldSpannerType(f); ok {
+			continue
+		}
+		if f.Type.Elem != nil {
+			c.warnf(f.Position, "%s of %s is ARRAY, so the CHECK constraint of enum %s is not added.", f.Name, def.Name, enum.Name)
+			continue
+		}
+		name, err := c.ConvertFieldName(f)
+		if err != nil {
+			return nil, err
+		}
+		in := spansql.InOp{LHS: spansql.ID(name)}
+		for _, v := range enum.EnumValues {
+			in.RHS = append(in.RHS, spansql.StringLiteral(v.Name))
+		}
+		constraints = append(constraints, spansql.TableConstraint{
+			Name:       spansql.ID(fmt.Sprintf("CK_%s_%s", sc.Name, name)),
+			Constraint: spansql.Check{Expr: in},
+		})
+	}
+	return constraints, nil
+}
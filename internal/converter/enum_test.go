@@ -0,0 +1,93 @@
+package converter_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/nktks/gql-spansql/internal/converter"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/enum.gql
+var enumBody []byte
+
+func TestConverter_Enum(t *testing.T) {
+	s, err := loadGQL(enumBody)
+	require.NoError(t, err)
+	t.Run("storages", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{Enums: map[string]string{"Color": "CHECK"}})
+		require.NoError(t, err)
+		sql, err := c.SpannerSQL()
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE User (
+  userId STRING(MAX) NOT NULL,
+  state STRING(MAX) NOT NULL,
+  role INT64 NOT NULL,
+  roles ARRAY<INT64> NOT NULL,
+  color STRING(MAX),
+  states ARRAY<STRING(MAX)>,
+  CONSTRAINT CK_User_state CHECK (state IN ("ENABLED", "DISABLED")),
+  CONSTRAINT CK_User_color CHECK (color IN ("RED", "BLUE")),
+) PRIMARY KEY(userId);
+`, sql)
+		var ds []string
+		for _, d := range c.Diagnostics() {
+			ds = append(ds, d.Message)
+		}
+		require.Equal(t, []string{"states of User is ARRAY, so the CHECK constraint of enum State is not added."}, ds)
+		pg, err := c.PostgreSQL()
+		require.NoError(t, err)
+		require.Contains(t, pg, `CONSTRAINT "CK_User_state" CHECK (state IN ('ENABLED', 'DISABLED')),`)
+	})
+	t.Run("diff", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{})
+		require.NoError(t, err)
+		current, err := spansql.ParseDDL("current.sql", `CREATE TABLE User (
+  userId STRING(MAX) NOT NULL,
+  state STRING(MAX) NOT NULL,
+  role INT64 NOT NULL,
+  roles ARRAY<INT64> NOT NULL,
+  color STRING(MAX),
+  states ARRAY<STRING(MAX)>,
+  CONSTRAINT CK_User_state CHECK (state IN ("ENABLED")),
+) PRIMARY KEY(userId);`)
+		require.NoError(t, err)
+		stmts, err := c.Diff(current)
+		require.NoError(t, err)
+		var sqls []string
+		for _, stmt := range stmts {
+			sqls = append(sqls, stmt.SQL())
+		}
+		require.Equal(t, []string{
+			"ALTER TABLE User DROP CONSTRAINT CK_User_state",
+			`ALTER TABLE User ADD CONSTRAINT CK_User_state CHECK (state IN ("ENABLED", "DISABLED"))`,
+		}, sqls)
+	})
+	for _, tc := range []struct {
+		name   string
+		member string
+		err    string
+	}{
+		{name: "missing number", member: "MEMBER", err: "-:8:3: value MEMBER of enum Role stored as INT64 has no @enumValue."},
+		{name: "duplicated number", member: "MEMBER @enumValue(number: 1)", err: "-:8:3: number 1 of enum Role is duplicated in ADMIN and MEMBER."},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := loadGQL([]byte(`enum Role @spannerEnum(storage: INT64) {
+  ADMIN @enumValue(number: 1)
+  ` + tc.member + `
+}
+
+type User {
+  userId: ID!
+  role: Role!
+}
+`))
+			require.NoError(t, err)
+			c, err := converter.New(s, converter.Options{})
+			require.NoError(t, err)
+			_, err = c.SpannerSQL()
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package converter
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// isRootType reports whether def is the query, mutation or subscription type of the schema,
+// including the root types renamed by the schema definition, e.g. schema { query: RootQuery }.
+func (c *Converter) isRootType(def *ast.Definition) bool {
+	for _, root := range []*ast.Definition{c.schema.Query, c.schema.Mutation, c.schema.Subscription} {
+		if root != nil && root.Name == def.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// excluded reports whether def is not converted to a table:
+// the root operation types, the types with @spannerIgnore, the types matching Options.ExcludeTypes
+// and, if Options.IncludeTypes is not empty, the types matching none of them.
+func (c *Converter) excluded(def *ast.Definition) bool {
+	if c.isRootType(def) {
+		return true
+	}
+	if def.Directives.ForName(spannerIgnoreDirective) != nil {
+		return true
+	}
+	if matchAny(c.excludeTypes, def.Name) {
+		return true
+	}
+	return len(c.includeTypes) > 0 && !matchAny(c.includeTypes, def.Name)
+}
+
+// isTable reports whether the object def is converted to its own table:
+// it is not excluded, stored in the single table of its interface, stored inline or a Relay type.
+func (c *Converter) isTable(def *ast.Definition) bool {
+	if def.Kind != ast.Object || c.excluded(def) || c.singleTableOf(def) != nil || c.storedInline(def) {
+		return false
+	}
+	return !c.relay || !isRelayType(def)
+}
+
+// validatePatterns returns an error if any of patterns is malformed.
+func validatePatterns(patterns []string) error {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("type pattern %s is invalid.", p)
+		}
+	}
+	return nil
+}
+
+// matchAny reports whether name matches any of the glob patterns.
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
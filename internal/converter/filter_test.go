@@ -0,0 +1,75 @@
+package converter_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/nktks/gql-spansql/internal/converter"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/filter.gql
+var filterBody []byte
+
+func TestConverter_Filter(t *testing.T) {
+	s, err := loadGQL(filterBody)
+	require.NoError(t, err)
+	t.Run("exclude", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{ExcludeTypes: []string{"*Payload"}})
+		require.NoError(t, err)
+		sql, err := c.SpannerSQL()
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE Team (
+  teamId STRING(MAX) NOT NULL,
+) PRIMARY KEY(teamId);
+CREATE TABLE User (
+  userId STRING(MAX) NOT NULL,
+  name STRING(MAX) NOT NULL,
+) PRIMARY KEY(userId);
+`, sql)
+	})
+	t.Run("include", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{IncludeTypes: []string{"U*", "*Payload"}, ExcludeTypes: []string{"*Payload"}})
+		require.NoError(t, err)
+		sql, err := c.SpannerSQL()
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE User (
+  userId STRING(MAX) NOT NULL,
+  name STRING(MAX) NOT NULL,
+) PRIMARY KEY(userId);
+`, sql)
+	})
+	t.Run("excluded interleave parent", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			schema string
+			opts   converter.Options
+		}{
+			"ignored": {schema: `
+type Team @spannerIgnore {
+  teamId: ID!
+}`},
+			"exclude": {schema: `
+type Team {
+  teamId: ID!
+}`, opts: converter.Options{ExcludeTypes: []string{"Team"}}},
+			"embedded": {schema: `
+type Team @embedded {
+  teamId: ID!
+}`},
+		} {
+			s, err := loadGQL([]byte(tc.schema + `
+type Member @interleave(in: "Team") {
+  memberId: ID!
+}`))
+			require.NoError(t, err, name)
+			c, err := converter.New(s, tc.opts)
+			require.NoError(t, err, name)
+			_, err = c.SpannerSQL()
+			require.ErrorContains(t, err, "interleave parent Team of Member is not converted to a table.", name)
+		}
+	})
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := converter.New(s, converter.Options{ExcludeTypes: []string{"[User"}})
+		require.EqualError(t, err, "type pattern [User is invalid.")
+	})
+}
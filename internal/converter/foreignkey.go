@@ -0,0 +1,141 @@
+package converter
+
+import (
+	"fmt"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/iancoleman/strcase"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// relationOf returns the object definition which f refers to, or nil if f is not a relation field.
+func (c *Converter) relationOf(f *ast.FieldDefinition) (*ast.Definition, bool) {
+	if isJSON(f) {
+		return nil, false
+	}
+	namedType := f.Type.NamedType
+	isArray := false
+	if namedType == "" {
+		isArray = true
+		namedType = f.Type.Elem.NamedType
+	}
+	def, ok := c.schema.Types[namedType]
+	if !ok {
+		return nil, false
+	}
+	if i := c.singleTableOf(def); i != nil {
+		return i, isArray
+	}
+	if def.Kind != ast.Object && c.inheritance(def) == "" {
+		return nil, false
+	}
+	return def, isArray
+}
+
+// ConvertFieldColumns converts f to columns.
+// a relation field to an object which has multiple primary key columns is converted to the column per key.
+// a union field is converted to the columns of the polymorphic reference.
+func (c *Converter) ConvertFieldColumns(f *ast.FieldDefinition) ([]spansql.ColumnDef, error) {
+	if u := c.unionOf(f); u != nil {
+		return c.unionColumns(f, u)
+	}
+	ref, isArray := c.relationOf(f)
+	if ref != nil && !isArray {
+		_, kcols, err := c.keyColumns(ref)
+		if err != nil {
+			return nil, err
+		}
+		if len(kcols) > 1 {
+			return c.relationColumns(f, ref, kcols)
+		}
+	}
+	col, err := c.ConvertField(f)
+	if err != nil {
+		return nil, err
+	}
+	return []spansql.ColumnDef{*col}, nil
+}
+
+func (c *Converter) relationColumns(f *ast.FieldDefinition, ref *ast.Definition, kcols []spansql.ColumnDef) ([]spansql.ColumnDef, error) {
+	if f.Directives.ForName(spannerColumnDirective) != nil || spanColumnRe.MatchString(f.Description) {
+		return nil, fmt.Errorf("%s: column name can not be specified to the relation to multiple pk keys of %s.", f.Name, ref.Name)
+	}
+	fieldCase := c.relationFieldCase(f)
+	cols := make([]spansql.ColumnDef, 0, len(kcols))
+	for _, k := range kcols {
+		name := f.Name + strcase.ToCamel(string(k.Name))
+		if NormalizeCase(string(k.Name)) == NormalizeCase("Id") || NormalizeCase(string(k.Name)) == NormalizeCase(ref.Name+"Id") {
+			name = f.Name + "Id"
+		}
+		cols = append(cols, spansql.ColumnDef{
+			Name:    spansql.ID(ConvertCase(name, fieldCase)),
+			Type:    k.Type,
+			NotNull: f.Type.NonNull,
+		})
+	}
+	return cols, nil
+}
+
+// foreignKeys returns FOREIGN KEY constraints of the relation fields of def.
+// they are added to all relation fields when the foreign key mode is enabled, or to the fields with @foreignKey.
+func (c *Converter) foreignKeys(def *ast.Definition, sc *spansql.CreateTable) ([]spansql.TableConstraint, error) {
+	var constraints []spansql.TableConstraint
+	for _, f := range c.fields(def) {
+		d := f.Directives.ForName(foreignKeyDirective)
+		enabled := c.foreignKey
+		if d != nil {
+			enabled = !boolArg(d, "disable")
+		}
+		if !enabled {
+			continue
+		}
+		if u := c.unionOf(f); u != nil {
+			fks, err := c.unionForeignKeys(f, u, d, sc)
+			if err != nil {
+				return nil, err
+			}
+			constraints = append(constraints, fks...)
+			continue
+		}
+		ref, isArray := c.relationOf(f)
+		if ref == nil || isArray {
+			if d != nil {
+				return nil, fmt.Errorf("%s: @%s is only allowed on the relation field to an object.", f.Name, foreignKeyDirective)
+			}
+			continue
+		}
+		if c.excluded(ref) {
+			if d != nil {
+				return nil, fmt.Errorf("%s: @%s references %s which is excluded.", f.Name, foreignKeyDirective, ref.Name)
+			}
+			continue
+		}
+		_, kcols, err := c.keyColumns(ref)
+		if err != nil {
+			return nil, err
+		}
+		cols, err := c.ConvertFieldColumns(f)
+		if err != nil {
+			return nil, err
+		}
+		fk := spansql.ForeignKey{
+			RefTable: spansql.ID(ConvertCase(ref.Name, c.tableCase)),
+		}
+		for i := range cols {
+			fk.Columns = append(fk.Columns, cols[i].Name)
+			fk.RefColumns = append(fk.RefColumns, kcols[i].Name)
+		}
+		name := fmt.Sprintf("FK_%s_%s", sc.Name, ConvertCase(f.Name, c.columnCase))
+		if d != nil {
+			fk.OnDelete = onDeleteArg(d)
+			if n, ok := stringArg(d, "name"); ok && n != "" {
+				name = n
+			}
+		}
+		constraints = append(constraints, spansql.TableConstraint{
+			Name:       spansql.ID(name),
+			Constraint: fk,
+		})
+	}
+	return constraints, nil
+}
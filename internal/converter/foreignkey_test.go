@@ -0,0 +1,131 @@
+package converter_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/nktks/gql-spansql/internal/converter"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/foreign_key.gql
+var foreignKeyBody []byte
+
+func TestConverter_ForeignKeys(t *testing.T) {
+	s, err := loadGQL(foreignKeyBody)
+	require.NoError(t, err)
+	t.Run("disabled", func(t *testing.T) {
+		c, err := converter.NewConverter(s, true, "", "", "", "")
+		require.NoError(t, err)
+		createTable, err := c.ConvertDefinition(s.Types["User"])
+		require.NoError(t, err)
+		require.Empty(t, createTable.Constraints)
+		createTable, err = c.ConvertDefinition(s.Types["Post"])
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE Post (
+  userId STRING(MAX) NOT NULL,
+  postId STRING(MAX) NOT NULL,
+  authorId STRING(MAX) NOT NULL,
+  CONSTRAINT FK_PostAuthor FOREIGN KEY (authorId) REFERENCES User (userId) ON DELETE CASCADE,
+) PRIMARY KEY(userId, postId),
+  INTERLEAVE IN PARENT User ON DELETE NO ACTION`, createTable.SQL())
+	})
+	t.Run("enabled", func(t *testing.T) {
+		c, err := converter.NewConverter(s, true, "", "", "", "", converter.WithForeignKeys(true))
+		require.NoError(t, err)
+		createTable, err := c.ConvertDefinition(s.Types["User"])
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE User (
+  userId STRING(MAX) NOT NULL,
+  managerId STRING(MAX),
+  favoritePostUserId STRING(MAX),
+  favoritePostId STRING(MAX),
+  CONSTRAINT FK_User_manager FOREIGN KEY (managerId) REFERENCES User (userId) ON DELETE NO ACTION,
+) PRIMARY KEY(userId)`, createTable.SQL())
+	})
+	t.Run("composite key", func(t *testing.T) {
+		c, err := converter.NewConverter(s, true, "", "", "", "", converter.WithForeignKeys(true))
+		require.NoError(t, err)
+		createTable, err := c.ConvertDefinition(s.Types["Comment"])
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE Comment (
+  commentId STRING(MAX) NOT NULL,
+  postUserId STRING(MAX) NOT NULL,
+  postId STRING(MAX) NOT NULL,
+  tags ARRAY<STRING(MAX)> NOT NULL,
+  CONSTRAINT FK_Comment_post FOREIGN KEY (postUserId, postId) REFERENCES Post (userId, postId) ON DELETE NO ACTION,
+) PRIMARY KEY(commentId)`, createTable.SQL())
+	})
+	t.Run("not a relation", func(t *testing.T) {
+		c, err := converter.NewConverter(s, true, "", "", "", "")
+		require.NoError(t, err)
+		_, err = c.ConvertDefinition(s.Types["Invalid"])
+		require.Error(t, err)
+	})
+	t.Run("circular references", func(t *testing.T) {
+		s, err := loadGQL([]byte(`
+type A {
+  aId: ID!
+  b: B
+}
+type B {
+  bId: ID!
+  a: A
+}
+`))
+		require.NoError(t, err)
+		c, err := converter.NewConverter(s, true, "", "", "", "", converter.WithForeignKeys(true))
+		require.NoError(t, err)
+		sql, err := c.SpannerSQL()
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE A (
+  aId STRING(MAX) NOT NULL,
+  bId STRING(MAX),
+) PRIMARY KEY(aId);
+CREATE TABLE B (
+  bId STRING(MAX) NOT NULL,
+  aId STRING(MAX),
+  CONSTRAINT FK_B_a FOREIGN KEY (aId) REFERENCES A (aId) ON DELETE NO ACTION,
+) PRIMARY KEY(bId);
+ALTER TABLE A ADD CONSTRAINT FK_A_b FOREIGN KEY (bId) REFERENCES B (bId) ON DELETE NO ACTION;
+`, sql)
+	})
+	t.Run("composite key part", func(t *testing.T) {
+		s, err := loadGQL([]byte(`
+type Tenant {
+  tenantId: ID! @spannerPK(order: 1)
+  region: String! @spannerPK(order: 2)
+}
+type Project {
+  tenant: Tenant! @spannerPK(order: 1) @index
+  projectId: ID! @spannerPK(order: 2)
+}
+type Task @interleave(in: "Project") {
+  taskId: ID!
+}
+`))
+		require.NoError(t, err)
+		c, err := converter.NewConverter(s, true, "", "", "", "")
+		require.NoError(t, err)
+		sql, err := c.SpannerSQL()
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE Project (
+  tenantId STRING(MAX) NOT NULL,
+  tenantRegion STRING(MAX) NOT NULL,
+  projectId STRING(MAX) NOT NULL,
+) PRIMARY KEY(tenantId, tenantRegion, projectId);
+CREATE INDEX ProjectByTenant ON Project(tenantId, tenantRegion);
+CREATE TABLE Task (
+  tenantId STRING(MAX) NOT NULL,
+  tenantRegion STRING(MAX) NOT NULL,
+  projectId STRING(MAX) NOT NULL,
+  taskId STRING(MAX) NOT NULL,
+) PRIMARY KEY(tenantId, tenantRegion, projectId, taskId),
+  INTERLEAVE IN PARENT Project ON DELETE NO ACTION;
+CREATE TABLE Tenant (
+  tenantId STRING(MAX) NOT NULL,
+  region STRING(MAX) NOT NULL,
+) PRIMARY KEY(tenantId, region);
+`, sql)
+	})
+}
@@ -0,0 +1,41 @@
+package converter
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// fields returns the fields of def which are stored in the table.
+// the fields with @spannerIgnore or @computed are skipped, and so are the fields with arguments,
+// which are resolvers rather than stored data, unless Options.FieldsWithArguments is enabled.
+// in the Relay mode, connection fields and the global ids of Node are also skipped.
+// the fields stored in JSON columns are annotated by @spannerJSON, and the fields of embedded objects are flattened into the fields of def.
+func (c *Converter) fields(def *ast.Definition) ast.FieldList {
+	fields := make(ast.FieldList, 0, len(def.Fields))
+	for _, f := range def.Fields {
+		if isIgnored(f) {
+			continue
+		}
+		if c.relayField(def, f) {
+			continue
+		}
+		if len(f.Arguments) > 0 && !c.fieldsWithArguments {
+			c.warnf(f.Position, "%s of %s is not converted to a column because it has arguments.", f.Name, def.Name)
+			continue
+		}
+		if c.jsonField(def, f) {
+			fields = append(fields, asJSON(f))
+			continue
+		}
+		if ref := c.embeddedOf(f); ref != nil && f.Type.Elem == nil {
+			fields = append(fields, c.embeddedFields(f, ref)...)
+			continue
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// isIgnored reports whether f is annotated by @spannerIgnore or @computed.
+func isIgnored(f *ast.FieldDefinition) bool {
+	return f.Directives.ForName(spannerIgnoreDirective) != nil || f.Directives.ForName(computedDirective) != nil
+}
@@ -0,0 +1,73 @@
+package converter_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/nktks/gql-spansql/internal/converter"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/ignore.gql
+var ignoreBody []byte
+
+func TestConverter_IgnoreFields(t *testing.T) {
+	s, err := loadGQL(ignoreBody)
+	require.NoError(t, err)
+	t.Run("default", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{})
+		require.NoError(t, err)
+		createTable, err := c.ConvertDefinition(s.Types["User"])
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE User (
+  userId STRING(MAX) NOT NULL,
+  name STRING(MAX) NOT NULL,
+) PRIMARY KEY(userId)`, createTable.SQL())
+		var ds []string
+		for _, d := range c.Diagnostics() {
+			ds = append(ds, d.Message)
+		}
+		require.Equal(t, []string{
+			"posts of User is not converted to a column because it has arguments.",
+			"avatarUrl of User is not converted to a column because it has arguments.",
+		}, ds)
+	})
+	t.Run("fields with arguments", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{FieldsWithArguments: true})
+		require.NoError(t, err)
+		createTable, err := c.ConvertDefinition(s.Types["User"])
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE User (
+  userId STRING(MAX) NOT NULL,
+  name STRING(MAX) NOT NULL,
+  postIds ARRAY<STRING(MAX)> NOT NULL,
+  avatarUrl STRING(MAX),
+) PRIMARY KEY(userId)`, createTable.SQL())
+	})
+	t.Run("ignored primary key", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{})
+		require.NoError(t, err)
+		createTable, err := c.ConvertDefinition(s.Types["Post"])
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE Post (
+  postId STRING(MAX) NOT NULL,
+  title STRING(MAX) NOT NULL,
+) PRIMARY KEY(postId)`, createTable.SQL())
+	})
+	t.Run("no columns", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{})
+		require.NoError(t, err)
+		createTable, err := c.ConvertDefinition(s.Types["Feed"])
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE Feed (
+  FeedId STRING(MAX),
+) PRIMARY KEY(FeedId)`, createTable.SQL())
+		createTable, err = c.ConvertDefinition(s.Types["Secret"])
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE Secret (
+  SecretId STRING(MAX),
+) PRIMARY KEY(SecretId)`, createTable.SQL())
+		_, err = c.SpannerSQL()
+		require.NoError(t, err)
+	})
+}
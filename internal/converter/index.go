@@ -0,0 +1,172 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/iancoleman/strcase"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// ConvertIndexes converts @index and @unique directives of def and its fields to CREATE INDEX statements.
+func (c *Converter) ConvertIndexes(def *ast.Definition) ([]*spansql.CreateIndex, error) {
+	ct, err := c.ConvertDefinition(def)
+	if err != nil {
+		return nil, err
+	}
+	return c.convertIndexes(def, ct)
+}
+
+func (c *Converter) convertIndexes(def *ast.Definition, ct *spansql.CreateTable) ([]*spansql.CreateIndex, error) {
+	var indexes []*spansql.CreateIndex
+	for _, d := range def.Directives.ForNames(indexDirective) {
+		columns := stringListArg(d, "columns")
+		if len(columns) == 0 {
+			return nil, fmt.Errorf("@%s of %s must have columns.", indexDirective, def.Name)
+		}
+		ci, err := c.convertIndex(def, ct, d, columns)
+		if err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, ci)
+	}
+	for _, f := range c.fields(def) {
+		for _, d := range f.Directives {
+			if d.Name != indexDirective && d.Name != uniqueDirective {
+				continue
+			}
+			columns := append([]string{f.Name}, stringListArg(d, "columns")...)
+			ci, err := c.convertIndex(def, ct, d, columns)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Name, err)
+			}
+			indexes = append(indexes, ci)
+		}
+	}
+	names := map[spansql.ID]bool{}
+	for _, ci := range indexes {
+		if names[ci.Name] {
+			return nil, fmt.Errorf("index %s of %s is duplicated.", ci.Name, def.Name)
+		}
+		names[ci.Name] = true
+	}
+	return indexes, nil
+}
+
+func (c *Converter) convertIndex(def *ast.Definition, ct *spansql.CreateTable, d *ast.Directive, columns []string) (*spansql.CreateIndex, error) {
+	ci := &spansql.CreateIndex{
+		Table:        ct.Name,
+		Unique:       d.Name == uniqueDirective || boolArg(d, "unique"),
+		NullFiltered: boolArg(d, "nullFiltered"),
+	}
+	nameParts := []string{def.Name, "By"}
+	for _, column := range columns {
+		ref := strings.Fields(column)
+		if len(ref) == 0 || len(ref) > 2 || (len(ref) == 2 && !strings.EqualFold(ref[1], "DESC") && !strings.EqualFold(ref[1], "ASC")) {
+			return nil, fmt.Errorf("index column %q of %s is invalid.", column, def.Name)
+		}
+		ids, err := c.indexColumns(def, ct, ref[0])
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			ci.Columns = append(ci.Columns, spansql.KeyPart{
+				Column: id,
+				Desc:   len(ref) == 2 && strings.EqualFold(ref[1], "DESC"),
+			})
+		}
+		nameParts = append(nameParts, strcase.ToCamel(ref[0]))
+	}
+	for _, column := range stringListArg(d, "storing") {
+		ids, err := c.indexColumns(def, ct, column)
+		if err != nil {
+			return nil, err
+		}
+		ci.Storing = append(ci.Storing, ids...)
+	}
+	if in, ok := stringArg(d, "interleaveIn"); ok && in != "" {
+		table, err := c.indexInterleave(def, in, ci.Columns)
+		if err != nil {
+			return nil, err
+		}
+		ci.Interleave = table
+	}
+	if name, ok := stringArg(d, "name"); ok && name != "" {
+		ci.Name = spansql.ID(name)
+	} else {
+		ci.Name = spansql.ID(ConvertCase(strings.Join(nameParts, ""), c.tableCase))
+	}
+	return ci, nil
+}
+
+// indexColumns resolves a field name or a column name of ct to the column names.
+func (c *Converter) indexColumns(def *ast.Definition, ct *spansql.CreateTable, ref string) ([]spansql.ID, error) {
+	ids, err := c.columnsOf(def, ct, ref)
+	if err != nil {
+		return nil, err
+	}
+	if ids == nil {
+		return nil, fmt.Errorf("index column %s of %s is not found.", ref, def.Name)
+	}
+	return ids, nil
+}
+
+// columnsOf resolves a field name or a column name of ct to the column names of ct, or nil if they are not in ct.
+// a relation to the type with multiple pk keys and a polymorphic relation are resolved to all of their columns.
+func (c *Converter) columnsOf(def *ast.Definition, ct *spansql.CreateTable, ref string) ([]spansql.ID, error) {
+	f := c.fields(def).ForName(ref)
+	if f == nil {
+		if col := findColumn(ct.Columns, spansql.ID(ref)); col != nil {
+			return []spansql.ID{col.Name}, nil
+		}
+		return nil, nil
+	}
+	cols, err := c.ConvertFieldColumns(f)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]spansql.ID, 0, len(cols))
+	for _, col := range cols {
+		if findColumn(ct.Columns, col.Name) == nil {
+			return nil, nil
+		}
+		ids = append(ids, col.Name)
+	}
+	return ids, nil
+}
+
+// indexInterleave returns the table name of in, which must be one of the interleave ancestors of def.
+// the index must start with the primary key columns of the ancestor, as spanner stores its entries in the rows of the ancestor.
+func (c *Converter) indexInterleave(def *ast.Definition, in string, columns []spansql.KeyPart) (spansql.ID, error) {
+	ancestor, err := c.InterleaveParent(def)
+	if err != nil {
+		return "", err
+	}
+	for ancestor != nil && ancestor.Name != in {
+		if ancestor, err = c.InterleaveParent(ancestor); err != nil {
+			return "", err
+		}
+	}
+	if ancestor == nil {
+		return "", fmt.Errorf("index of %s can not be interleaved in %s which is not an interleave ancestor.", def.Name, in)
+	}
+	kp, _, err := c.keyColumns(ancestor)
+	if err != nil {
+		return "", err
+	}
+	for i, k := range kp {
+		if i >= len(columns) || columns[i].Column != k.Column {
+			return "", fmt.Errorf("index of %s interleaved in %s must start with its primary key columns %s.", def.Name, in, keyPartNames(kp))
+		}
+	}
+	return spansql.ID(ConvertCase(ancestor.Name, c.tableCase)), nil
+}
+
+func keyPartNames(kp []spansql.KeyPart) string {
+	names := make([]string, 0, len(kp))
+	for _, k := range kp {
+		names = append(names, string(k.Column))
+	}
+	return strings.Join(names, ", ")
+}
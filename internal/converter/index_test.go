@@ -0,0 +1,74 @@
+package converter_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/nktks/gql-spansql/internal/converter"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/index.gql
+var indexBody []byte
+
+func TestConverter_ConvertIndexes(t *testing.T) {
+	s, err := loadGQL(indexBody)
+	require.NoError(t, err)
+	t.Run("field and type directives", func(t *testing.T) {
+		c, err := converter.NewConverter(s, true, "", "", "", "")
+		require.NoError(t, err)
+		indexes, err := c.ConvertIndexes(s.Types["User"])
+		require.NoError(t, err)
+		var sqls []string
+		for _, ci := range indexes {
+			sqls = append(sqls, ci.SQL())
+		}
+		require.Equal(t, []string{
+			"CREATE INDEX UserByStateCreatedAt ON User(state, createdAt DESC) STORING (name)",
+			"CREATE NULL_FILTERED INDEX UserByNameAndState ON User(name, state)",
+			"CREATE INDEX UserByName ON User(name)",
+			"CREATE UNIQUE INDEX UserByEmail ON User(email)",
+		}, sqls)
+	})
+	t.Run("index name case", func(t *testing.T) {
+		c, err := converter.NewConverter(s, true, "", "", "snake", "snake")
+		require.NoError(t, err)
+		indexes, err := c.ConvertIndexes(s.Types["User"])
+		require.NoError(t, err)
+		require.Equal(t, "CREATE INDEX user_by_state_created_at ON user(state, created_at DESC) STORING (name)", indexes[0].SQL())
+	})
+	t.Run("interleave", func(t *testing.T) {
+		c, err := converter.NewConverter(s, true, "", "", "", "")
+		require.NoError(t, err)
+		indexes, err := c.ConvertIndexes(s.Types["Post"])
+		require.NoError(t, err)
+		require.Equal(t, "CREATE INDEX PostByUserIdTitle ON Post(userId, title), INTERLEAVE IN User", indexes[0].SQL())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		c, err := converter.NewConverter(s, true, "", "", "", "")
+		require.NoError(t, err)
+		for _, name := range []string{"NoColumns", "UnknownColumn", "NotAncestor", "Comment", "InterleavedInItself", "Duplicated"} {
+			_, err := c.ConvertIndexes(s.Types[name])
+			require.Error(t, err, name)
+		}
+	})
+	t.Run("spanner sql", func(t *testing.T) {
+		s, err := loadGQL([]byte(`
+type User {
+  userId: ID!
+  email: String! @unique
+}
+`))
+		require.NoError(t, err)
+		c, err := converter.NewConverter(s, true, "", "", "", "")
+		require.NoError(t, err)
+		sql, err := c.SpannerSQL()
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE User (
+  userId STRING(MAX) NOT NULL,
+  email STRING(MAX) NOT NULL,
+) PRIMARY KEY(userId);
+CREATE UNIQUE INDEX UserByEmail ON User(email);
+`, sql)
+	})
+}
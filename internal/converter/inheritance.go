@@ -0,0 +1,94 @@
+package converter
+
+import (
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const (
+	singleTableInheritance  = "SINGLE_TABLE"
+	tablePerTypeInheritance = "TABLE_PER_TYPE"
+	defaultDiscriminator    = "type"
+)
+
+// inheritance returns how the implementing types of the interface def are stored,
+// by @inheritance or Options.Interfaces. it is empty if def is not an interface or is not converted.
+func (c *Converter) inheritance(def *ast.Definition) string {
+	if def == nil || def.Kind != ast.Interface {
+		return ""
+	}
+	if d := def.Directives.ForName(inheritanceDirective); d != nil {
+		s, _ := stringArg(d, "strategy")
+		return s
+	}
+	return c.interfaces[def.Name]
+}
+
+// singleTableOf returns the interface whose single table stores the object def, or nil.
+func (c *Converter) singleTableOf(def *ast.Definition) *ast.Definition {
+	if def.Kind != ast.Object {
+		return nil
+	}
+	for _, name := range def.Interfaces {
+		if i := c.schema.Types[name]; c.inheritance(i) == singleTableInheritance {
+			return i
+		}
+	}
+	return nil
+}
+
+// singleTable returns the definition of the table which stores all implementing types of the interface def:
+// the fields of def, the discriminator column of the type name, and the other fields of the implementing types,
+// which are nullable because the other types do not have them.
+func (c *Converter) singleTable(def *ast.Definition) (*ast.Definition, error) {
+	t := &ast.Definition{
+		Kind:        def.Kind,
+		Name:        def.Name,
+		Description: def.Description,
+		Directives:  def.Directives,
+		Position:    def.Position,
+	}
+	t.Fields = append(t.Fields, def.Fields...)
+	discriminator := defaultDiscriminator
+	if d := def.Directives.ForName(inheritanceDirective); d != nil {
+		if n, ok := stringArg(d, "discriminator"); ok && n != "" {
+			discriminator = n
+		}
+	}
+	if t.Fields.ForName(discriminator) != nil {
+		return nil, fmt.Errorf("discriminator %s of %s is already defined as a field.", discriminator, def.Name)
+	}
+	t.Fields = append(t.Fields, &ast.FieldDefinition{
+		Name:     discriminator,
+		Type:     ast.NonNullNamedType("String", nil),
+		Position: def.Position,
+	})
+	owners := map[string]string{}
+	for _, impl := range c.schema.GetPossibleTypes(def) {
+		if other := c.singleTableOf(impl); other != nil && other.Name != def.Name {
+			return nil, fmt.Errorf("%s implements %s and %s which are both stored in a single table.", impl.Name, other.Name, def.Name)
+		}
+		for _, f := range impl.Fields {
+			if def.Fields.ForName(f.Name) != nil {
+				continue
+			}
+			if f.Name == discriminator {
+				return nil, fmt.Errorf("discriminator %s of %s is already defined as a field of %s.", discriminator, def.Name, impl.Name)
+			}
+			if other := t.Fields.ForName(f.Name); other != nil {
+				if other.Type.Name() != f.Type.Name() || (other.Type.Elem == nil) != (f.Type.Elem == nil) {
+					return nil, fmt.Errorf("%s of %s conflicts with %s of %s in the single table %s.", f.Name, impl.Name, f.Name, owners[f.Name], def.Name)
+				}
+				continue
+			}
+			nullable := *f.Type
+			nullable.NonNull = false
+			field := *f
+			field.Type = &nullable
+			t.Fields = append(t.Fields, &field)
+			owners[f.Name] = impl.Name
+		}
+	}
+	return t, nil
+}
@@ -0,0 +1,115 @@
+package converter_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/nktks/gql-spansql/internal/converter"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/inheritance.gql
+var inheritanceBody []byte
+
+func TestConverter_Inheritance(t *testing.T) {
+	s, err := loadGQL(inheritanceBody)
+	require.NoError(t, err)
+	t.Run("strategies", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{
+			ForeignKeys: true,
+			Interfaces:  map[string]string{"Document": "TABLE_PER_TYPE"},
+		})
+		require.NoError(t, err)
+		sql, err := c.SpannerSQL()
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE Animal (
+  id STRING(MAX) NOT NULL,
+  name STRING(MAX) NOT NULL,
+  kind STRING(MAX) NOT NULL,
+  barks BOOL,
+  lives INT64,
+) PRIMARY KEY(id);
+CREATE TABLE Invoice (
+  id STRING(MAX) NOT NULL,
+  title STRING(MAX) NOT NULL,
+  amount FLOAT64 NOT NULL,
+) PRIMARY KEY(id);
+CREATE TABLE Memo (
+  id STRING(MAX) NOT NULL,
+  title STRING(MAX) NOT NULL,
+) PRIMARY KEY(id);
+CREATE TABLE Owner (
+  ownerId STRING(MAX) NOT NULL,
+  petId STRING(MAX),
+  dogId STRING(MAX) NOT NULL,
+  documentType STRING(MAX) NOT NULL,
+  documentId STRING(MAX) NOT NULL,
+  CONSTRAINT FK_Owner_pet FOREIGN KEY (petId) REFERENCES Animal (id) ON DELETE NO ACTION,
+  CONSTRAINT FK_Owner_dog FOREIGN KEY (dogId) REFERENCES Animal (id) ON DELETE NO ACTION,
+) PRIMARY KEY(ownerId);
+`, sql)
+		require.Empty(t, c.Diagnostics())
+	})
+	t.Run("table per type reference", func(t *testing.T) {
+		s, err := loadGQL([]byte(`interface Document @inheritance(strategy: TABLE_PER_TYPE) {
+  id: ID!
+}
+
+type Invoice implements Document {
+  id: ID!
+}
+
+type Memo implements Document {
+  id: ID!
+}
+
+type Folder {
+  folderId: ID!
+  document: Document @polymorphic(strategy: COLUMN_PER_TYPE) @foreignKey
+}
+`))
+		require.NoError(t, err)
+		c, err := converter.New(s, converter.Options{})
+		require.NoError(t, err)
+		createTable, err := c.ConvertDefinition(s.Types["Folder"])
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE Folder (
+  folderId STRING(MAX) NOT NULL,
+  documentInvoiceId STRING(MAX),
+  documentMemoId STRING(MAX),
+  CONSTRAINT FK_Folder_documentInvoice FOREIGN KEY (documentInvoiceId) REFERENCES Invoice (id) ON DELETE NO ACTION,
+  CONSTRAINT FK_Folder_documentMemo FOREIGN KEY (documentMemoId) REFERENCES Memo (id) ON DELETE NO ACTION,
+) PRIMARY KEY(folderId)`, createTable.SQL())
+	})
+	t.Run("no strategy", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{})
+		require.NoError(t, err)
+		_, err = c.SpannerSQL()
+		require.EqualError(t, err, "-:38:3: interface Document is not converted to a table. @inheritance is required to refer to it.")
+	})
+	t.Run("unknown strategy", func(t *testing.T) {
+		_, err := converter.New(s, converter.Options{Interfaces: map[string]string{"Document": "JOINED"}})
+		require.EqualError(t, err, "inheritance strategy JOINED of Document not found.")
+	})
+	t.Run("conflict", func(t *testing.T) {
+		s, err := loadGQL([]byte(`interface Animal @inheritance(strategy: SINGLE_TABLE) {
+  id: ID!
+}
+
+type Dog implements Animal {
+  id: ID!
+  size: Int!
+}
+
+type Cat implements Animal {
+  id: ID!
+  size: String!
+}
+`))
+		require.NoError(t, err)
+		c, err := converter.New(s, converter.Options{})
+		require.NoError(t, err)
+		_, err = c.SpannerSQL()
+		require.ErrorContains(t, err, "size of Cat conflicts with size of Dog in the single table Animal.")
+	})
+}
@@ -0,0 +1,164 @@
+package converter
+
+import (
+	"fmt"
+	"math"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// maxInterleaveDepth is the limit of the interleaving depth of spanner.
+// https://cloud.google.com/spanner/quotas#tables
+const maxInterleaveDepth = 7
+
+// InterleaveParent returns the definition which def is interleaved in, or nil if def is not interleaved.
+func (c *Converter) InterleaveParent(def *ast.Definition) (*ast.Definition, error) {
+	d := def.Directives.ForName(interleaveDirective)
+	if d == nil {
+		return nil, nil
+	}
+	in, _ := stringArg(d, "in")
+	parent, ok := c.schema.Types[in]
+	if !ok || parent.BuiltIn {
+		return nil, fmt.Errorf("interleave parent %s of %s is not found.", in, def.Name)
+	}
+	if parent.Kind != ast.Object {
+		return nil, fmt.Errorf("interleave parent %s of %s is not an object.", in, def.Name)
+	}
+	if !c.isTable(parent) {
+		return nil, fmt.Errorf("interleave parent %s of %s is not converted to a table.", in, def.Name)
+	}
+	return parent, nil
+}
+
+// interleaveDepth returns the number of ancestors of def.
+func (c *Converter) interleaveDepth(def *ast.Definition) (int, error) {
+	depth := 0
+	visited := map[string]bool{def.Name: true}
+	for cur := def; ; {
+		parent, err := c.InterleaveParent(cur)
+		if err != nil {
+			return 0, err
+		}
+		if parent == nil {
+			return depth, nil
+		}
+		if visited[parent.Name] {
+			return 0, fmt.Errorf("interleave of %s is circular.", def.Name)
+		}
+		visited[parent.Name] = true
+		depth++
+		if depth > maxInterleaveDepth {
+			return 0, fmt.Errorf("interleave depth of %s exceeds %d.", def.Name, maxInterleaveDepth)
+		}
+		cur = parent
+	}
+}
+
+// interleave sets the interleave clause to sc and prepends the primary key of the parent to sc.
+func (c *Converter) interleave(def *ast.Definition, sc *spansql.CreateTable) error {
+	parent, err := c.InterleaveParent(def)
+	if err != nil || parent == nil {
+		return err
+	}
+	if _, err := c.interleaveDepth(def); err != nil {
+		return err
+	}
+	parentPK, parentCols, err := c.keyColumns(parent)
+	if err != nil {
+		return err
+	}
+	sc.Interleave = &spansql.Interleave{
+		Parent:   spansql.ID(ConvertCase(parent.Name, c.tableCase)),
+		OnDelete: onDeleteArg(def.Directives.ForName(interleaveDirective)),
+	}
+
+	parentKeys := map[spansql.ID]bool{}
+	var keyColumns []spansql.ColumnDef
+	for i, kp := range parentPK {
+		parentKeys[kp.Column] = true
+		pcol := &parentCols[i]
+		if col := findColumn(sc.Columns, kp.Column); col != nil {
+			if col.Type != pcol.Type {
+				return fmt.Errorf("column %s of %s must be same type as interleave parent %s.", kp.Column, def.Name, parent.Name)
+			}
+			col.NotNull = true
+			continue
+		}
+		keyColumns = append(keyColumns, *pcol)
+	}
+	sc.Columns = append(keyColumns, sc.Columns...)
+
+	pk := append([]spansql.KeyPart{}, parentPK...)
+	own := 0
+	for _, kp := range sc.PrimaryKey {
+		if parentKeys[kp.Column] {
+			continue
+		}
+		pk = append(pk, kp)
+		own++
+	}
+	if own == 0 {
+		return fmt.Errorf("interleaved table %s must have primary key columns other than parent %s.", def.Name, parent.Name)
+	}
+	sc.PrimaryKey = pk
+	return nil
+}
+
+// keyColumns returns the primary key of def and its columns, including the keys of interleave ancestors.
+func (c *Converter) keyColumns(def *ast.Definition) ([]spansql.KeyPart, []spansql.ColumnDef, error) {
+	var kp []spansql.KeyPart
+	var cols []spansql.ColumnDef
+	parent, err := c.InterleaveParent(def)
+	if err != nil {
+		return nil, nil, err
+	}
+	if parent != nil {
+		if _, err := c.interleaveDepth(def); err != nil {
+			return nil, nil, err
+		}
+		kp, cols, err = c.keyColumns(parent)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	pk, found := c.DetectPK(def.Name, c.fields(def))
+	if !found {
+		kp = append(kp, pk[0])
+		cols = append(cols, spansql.ColumnDef{
+			Name: pk[0].Column,
+			Type: spansql.Type{
+				Base: spansql.String,
+				Len:  math.MaxInt64,
+			},
+			NotNull: true,
+		})
+		return kp, cols, nil
+	}
+	parts, _ := c.detectPKParts(def.Name, c.fields(def))
+	for _, p := range parts {
+		pcols, err := c.ConvertFieldColumns(p.field)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, col := range pcols {
+			if findColumn(cols, col.Name) != nil {
+				continue
+			}
+			col.NotNull = true
+			kp = append(kp, spansql.KeyPart{Column: col.Name, Desc: p.desc})
+			cols = append(cols, col)
+		}
+	}
+	return kp, cols, nil
+}
+
+func findColumn(cols []spansql.ColumnDef, name spansql.ID) *spansql.ColumnDef {
+	for i := range cols {
+		if cols[i].Name == name {
+			return &cols[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package converter_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/nktks/gql-spansql/internal/converter"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/interleave.gql
+var interleaveBody []byte
+
+func TestConverter_Interleave(t *testing.T) {
+	s, err := loadGQL(interleaveBody)
+	require.NoError(t, err)
+	c, err := converter.NewConverter(s, true, "", "", "", "")
+	require.NoError(t, err)
+	t.Run("child", func(t *testing.T) {
+		createTable, err := c.ConvertDefinition(s.Types["Purchase"])
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE Purchase (
+  purchaseId STRING(MAX) NOT NULL,
+  userId STRING(MAX) NOT NULL,
+) PRIMARY KEY(userId, purchaseId),
+  INTERLEAVE IN PARENT User ON DELETE CASCADE`, createTable.SQL())
+	})
+	t.Run("grandchild", func(t *testing.T) {
+		createTable, err := c.ConvertDefinition(s.Types["PurchaseItem"])
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE PurchaseItem (
+  userId STRING(MAX) NOT NULL,
+  purchaseId STRING(MAX) NOT NULL,
+  purchaseItemId STRING(MAX) NOT NULL,
+  count INT64 NOT NULL,
+) PRIMARY KEY(userId, purchaseId, purchaseItemId),
+  INTERLEAVE IN PARENT Purchase ON DELETE NO ACTION`, createTable.SQL())
+	})
+	t.Run("same key as parent", func(t *testing.T) {
+		_, err := c.ConvertDefinition(s.Types["SameKey"])
+		require.Error(t, err)
+	})
+	t.Run("missing parent", func(t *testing.T) {
+		_, err := c.ConvertDefinition(s.Types["MissingParent"])
+		require.Error(t, err)
+	})
+	t.Run("circular", func(t *testing.T) {
+		_, err := c.ConvertDefinition(s.Types["Circular1"])
+		require.Error(t, err)
+	})
+	t.Run("depth", func(t *testing.T) {
+		_, err := c.ConvertDefinition(s.Types["Depth7"])
+		require.NoError(t, err)
+		_, err = c.ConvertDefinition(s.Types["Depth8"])
+		require.Error(t, err)
+	})
+	t.Run("parent comes first", func(t *testing.T) {
+		s, err := loadGQL([]byte(`
+type A @interleave(in: "B") {
+  aId: ID!
+}
+type B {
+  bId: ID!
+}
+`))
+		require.NoError(t, err)
+		c, err := converter.NewConverter(s, true, "", "", "", "")
+		require.NoError(t, err)
+		sql, err := c.SpannerSQL()
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE B (
+  bId STRING(MAX) NOT NULL,
+) PRIMARY KEY(bId);
+CREATE TABLE A (
+  bId STRING(MAX) NOT NULL,
+  aId STRING(MAX) NOT NULL,
+) PRIMARY KEY(bId, aId),
+  INTERLEAVE IN PARENT B ON DELETE NO ACTION;
+`, sql)
+	})
+}
@@ -0,0 +1,40 @@
+package converter
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// jsonField reports whether the field f of def is stored in a JSON column:
+// f or its object type has @spannerJSON, def.f matches Options.JSONFields,
+// or f is a nested list and the JSON mode is enabled.
+func (c *Converter) jsonField(def *ast.Definition, f *ast.FieldDefinition) bool {
+	if isJSON(f) {
+		return true
+	}
+	if ref, ok := c.schema.Types[f.Type.Name()]; ok && ref.Kind == ast.Object && ref.Directives.ForName(spannerJSONDirective) != nil {
+		return true
+	}
+	if matchAny(c.jsonFields, def.Name+"."+f.Name) {
+		return true
+	}
+	return c.json && isNestedList(f.Type)
+}
+
+// asJSON returns the copy of f annotated by @spannerJSON, so that the conversions of f do not need its owner.
+func asJSON(f *ast.FieldDefinition) *ast.FieldDefinition {
+	if isJSON(f) {
+		return f
+	}
+	field := *f
+	field.Directives = append(ast.DirectiveList{{Name: spannerJSONDirective, Position: f.Position}}, f.Directives...)
+	return &field
+}
+
+// isJSON reports whether f is annotated by @spannerJSON.
+func isJSON(f *ast.FieldDefinition) bool {
+	return f.Directives.ForName(spannerJSONDirective) != nil
+}
+
+func isNestedList(t *ast.Type) bool {
+	return t.Elem != nil && t.Elem.Elem != nil
+}
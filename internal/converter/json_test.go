@@ -0,0 +1,37 @@
+package converter_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/nktks/gql-spansql/internal/converter"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/json.gql
+var jsonBody []byte
+
+func TestConverter_JSON(t *testing.T) {
+	s, err := loadGQL(jsonBody)
+	require.NoError(t, err)
+	t.Run("json mode", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{JSON: true, JSONFields: []string{"User.meta*"}, ForeignKeys: true})
+		require.NoError(t, err)
+		sql, err := c.SpannerSQL()
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE User (
+  userId STRING(MAX) NOT NULL,
+  settings JSON NOT NULL,
+  matrix JSON,
+  tags JSON NOT NULL,
+  metadata JSON,
+) PRIMARY KEY(userId);
+`, sql)
+	})
+	t.Run("nested list", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{})
+		require.NoError(t, err)
+		_, err = c.SpannerSQL()
+		require.EqualError(t, err, "-:14:3: matrix: nested list is not allowed in ARRAY. use @spannerJSON to store it as JSON.")
+	})
+}
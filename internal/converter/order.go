@@ -0,0 +1,70 @@
+package converter
+
+import (
+	"cloud.google.com/go/spanner/spansql"
+)
+
+// sortTables sorts tables so that interleave parents and tables referenced by foreign keys come first,
+// keeping the given order as much as possible.
+// foreign keys which can not be ordered because of circular references are removed from tables
+// and returned as ALTER TABLE statements to be applied after all tables are created.
+func sortTables(tables []*spansql.CreateTable) ([]*spansql.CreateTable, []*spansql.AlterTable) {
+	exists := map[spansql.ID]bool{}
+	for _, ct := range tables {
+		exists[ct.Name] = true
+	}
+	created := map[spansql.ID]bool{}
+	ready := func(id spansql.ID) bool {
+		return !exists[id] || created[id]
+	}
+	parentCreated := func(ct *spansql.CreateTable) bool {
+		return ct.Interleave == nil || ready(ct.Interleave.Parent)
+	}
+	refsCreated := func(ct *spansql.CreateTable) bool {
+		for _, tc := range ct.Constraints {
+			if fk, ok := tc.Constraint.(spansql.ForeignKey); ok && fk.RefTable != ct.Name && !ready(fk.RefTable) {
+				return false
+			}
+		}
+		return true
+	}
+
+	sorted := make([]*spansql.CreateTable, 0, len(tables))
+	var deferred []*spansql.AlterTable
+	remaining := append([]*spansql.CreateTable{}, tables...)
+	for len(remaining) > 0 {
+		pick := -1
+		for i, ct := range remaining {
+			if parentCreated(ct) && refsCreated(ct) {
+				pick = i
+				break
+			}
+		}
+		if pick == -1 {
+			// circular references. create the first table which can be created and defer its foreign keys.
+			for i, ct := range remaining {
+				if parentCreated(ct) {
+					pick = i
+					break
+				}
+			}
+		}
+		ct := remaining[pick]
+		remaining = append(remaining[:pick], remaining[pick+1:]...)
+		var constraints []spansql.TableConstraint
+		for _, tc := range ct.Constraints {
+			if fk, ok := tc.Constraint.(spansql.ForeignKey); ok && fk.RefTable != ct.Name && !ready(fk.RefTable) {
+				deferred = append(deferred, &spansql.AlterTable{
+					Name:       ct.Name,
+					Alteration: spansql.AddConstraint{Constraint: tc},
+				})
+				continue
+			}
+			constraints = append(constraints, tc)
+		}
+		ct.Constraints = constraints
+		created[ct.Name] = true
+		sorted = append(sorted, ct)
+	}
+	return sorted, deferred
+}
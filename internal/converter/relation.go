@@ -0,0 +1,142 @@
+package converter
+
+import (
+	"fmt"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/iancoleman/strcase"
+	"github.com/jinzhu/inflection"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const (
+	arrayRelation      = "ARRAY"
+	manyToManyRelation = "MANY_TO_MANY"
+)
+
+// relationKind returns how the relation field f is stored. it is empty if f is not a relation field.
+func (c *Converter) relationKind(f *ast.FieldDefinition) (string, error) {
+	ref, isArray := c.relationOf(f)
+	if d := f.Directives.ForName(relationDirective); d != nil {
+		kind, _ := stringArg(d, "kind")
+		if ref == nil || (kind == manyToManyRelation && !isArray) {
+			return "", fmt.Errorf("%s: %s relation is only allowed on the list of objects.", f.Name, kind)
+		}
+		return kind, nil
+	}
+	if ref == nil {
+		return "", nil
+	}
+	if isArray && c.manyToMany {
+		return manyToManyRelation, nil
+	}
+	return arrayRelation, nil
+}
+
+// ConvertJoinTables converts the many-to-many relation fields of def to association tables,
+// which have the primary keys of both sides as the primary key.
+func (c *Converter) ConvertJoinTables(def *ast.Definition) ([]*spansql.CreateTable, error) {
+	var tables []*spansql.CreateTable
+	for _, f := range c.fields(def) {
+		kind, err := c.relationKind(f)
+		if err != nil {
+			return nil, err
+		}
+		if kind != manyToManyRelation {
+			continue
+		}
+		jt, err := c.convertJoinTable(def, f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		tables = append(tables, jt)
+	}
+	return tables, nil
+}
+
+func (c *Converter) convertJoinTable(def *ast.Definition, f *ast.FieldDefinition) (*spansql.CreateTable, error) {
+	ref, _ := c.relationOf(f)
+	d := f.Directives.ForName(relationDirective)
+	name := ConvertCase(def.Name+strcase.ToCamel(f.Name), c.tableCase)
+	if d != nil {
+		if n, ok := stringArg(d, "table"); ok && n != "" {
+			name = n
+		}
+	}
+	jt := &spansql.CreateTable{
+		Name: spansql.ID(name),
+	}
+	ownerTable := spansql.ID(ConvertCase(def.Name, c.tableCase))
+	ownerPK, ownerCols, err := c.keyColumns(def)
+	if err != nil {
+		return nil, err
+	}
+	jt.Columns = append(jt.Columns, ownerCols...)
+	jt.PrimaryKey = append(jt.PrimaryKey, ownerPK...)
+
+	_, refCols, err := c.keyColumns(ref)
+	if err != nil {
+		return nil, err
+	}
+	single := *f
+	single.Name = inflection.Singular(f.Name)
+	single.Type = f.Type.Elem
+	single.Directives = nil
+	single.Description = ""
+	var targetCols []spansql.ColumnDef
+	if len(refCols) > 1 {
+		targetCols, err = c.relationColumns(&single, ref, refCols)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		name, err := c.ConvertFieldName(&single)
+		if err != nil {
+			return nil, err
+		}
+		col := refCols[0]
+		col.Name = spansql.ID(name)
+		targetCols = []spansql.ColumnDef{col}
+	}
+	for _, col := range targetCols {
+		if findColumn(jt.Columns, col.Name) != nil {
+			return nil, fmt.Errorf("column %s of join table %s is duplicated.", col.Name, name)
+		}
+		col.NotNull = true
+		jt.Columns = append(jt.Columns, col)
+		jt.PrimaryKey = append(jt.PrimaryKey, spansql.KeyPart{Column: col.Name})
+	}
+
+	interleave := d != nil && boolArg(d, "interleave")
+	if interleave {
+		jt.Interleave = &spansql.Interleave{
+			Parent:   ownerTable,
+			OnDelete: spansql.CascadeOnDelete,
+		}
+	}
+	if c.foreignKey {
+		if !interleave {
+			jt.Constraints = append(jt.Constraints, joinForeignKey(
+				fmt.Sprintf("FK_%s_%s", jt.Name, ownerTable), ownerTable, ownerCols, ownerCols))
+		}
+		if c.inheritance(ref) != tablePerTypeInheritance {
+			jt.Constraints = append(jt.Constraints, joinForeignKey(
+				fmt.Sprintf("FK_%s_%s", jt.Name, ConvertCase(single.Name, c.columnCase)), spansql.ID(ConvertCase(ref.Name, c.tableCase)), targetCols, refCols))
+		}
+	}
+	return jt, nil
+}
+
+func joinForeignKey(name string, refTable spansql.ID, cols, refCols []spansql.ColumnDef) spansql.TableConstraint {
+	fk := spansql.ForeignKey{
+		RefTable: refTable,
+	}
+	for i := range cols {
+		fk.Columns = append(fk.Columns, cols[i].Name)
+		fk.RefColumns = append(fk.RefColumns, refCols[i].Name)
+	}
+	return spansql.TableConstraint{
+		Name:       spansql.ID(name),
+		Constraint: fk,
+	}
+}
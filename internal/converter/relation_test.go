@@ -0,0 +1,94 @@
+package converter_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/nktks/gql-spansql/internal/converter"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/relation.gql
+var relationBody []byte
+
+func TestConverter_ConvertJoinTables(t *testing.T) {
+	s, err := loadGQL(relationBody)
+	require.NoError(t, err)
+	t.Run("directive", func(t *testing.T) {
+		c, err := converter.NewConverter(s, true, "", "", "", "")
+		require.NoError(t, err)
+		createTable, err := c.ConvertDefinition(s.Types["User"])
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE User (
+  userId STRING(MAX) NOT NULL,
+  tagIds ARRAY<STRING(MAX)> NOT NULL,
+  postIds ARRAY<STRING(MAX)> NOT NULL,
+) PRIMARY KEY(userId)`, createTable.SQL())
+		joinTables, err := c.ConvertJoinTables(s.Types["User"])
+		require.NoError(t, err)
+		require.Len(t, joinTables, 2)
+		require.Equal(t, `CREATE TABLE UserItems (
+  userId STRING(MAX) NOT NULL,
+  itemId STRING(MAX) NOT NULL,
+) PRIMARY KEY(userId, itemId)`, joinTables[0].SQL())
+		require.Equal(t, `CREATE TABLE Friendship (
+  userId STRING(MAX) NOT NULL,
+  friendId STRING(MAX) NOT NULL,
+) PRIMARY KEY(userId, friendId),
+  INTERLEAVE IN PARENT User ON DELETE CASCADE`, joinTables[1].SQL())
+	})
+	t.Run("global", func(t *testing.T) {
+		c, err := converter.NewConverter(s, true, "", "", "", "", converter.WithManyToMany(true), converter.WithForeignKeys(true))
+		require.NoError(t, err)
+		createTable, err := c.ConvertDefinition(s.Types["User"])
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE User (
+  userId STRING(MAX) NOT NULL,
+  postIds ARRAY<STRING(MAX)> NOT NULL,
+) PRIMARY KEY(userId)`, createTable.SQL())
+		joinTables, err := c.ConvertJoinTables(s.Types["User"])
+		require.NoError(t, err)
+		require.Len(t, joinTables, 3)
+		require.Equal(t, `CREATE TABLE UserTags (
+  userId STRING(MAX) NOT NULL,
+  tagId STRING(MAX) NOT NULL,
+  CONSTRAINT FK_UserTags_User FOREIGN KEY (userId) REFERENCES User (userId) ON DELETE NO ACTION,
+  CONSTRAINT FK_UserTags_tag FOREIGN KEY (tagId) REFERENCES Tag (id) ON DELETE NO ACTION,
+) PRIMARY KEY(userId, tagId)`, joinTables[2].SQL())
+	})
+	t.Run("composite key", func(t *testing.T) {
+		c, err := converter.NewConverter(s, true, "", "", "", "", converter.WithManyToMany(true))
+		require.NoError(t, err)
+		joinTables, err := c.ConvertJoinTables(s.Types["Item"])
+		require.NoError(t, err)
+		require.Empty(t, joinTables)
+		s, err := loadGQL([]byte(`
+type User {
+  userId: ID!
+}
+type Post @interleave(in: "User") {
+  postId: ID!
+}
+type Tag {
+  tagId: ID!
+  posts: [Post!]! @relation(kind: MANY_TO_MANY)
+}
+`))
+		require.NoError(t, err)
+		c, err = converter.NewConverter(s, true, "", "", "", "")
+		require.NoError(t, err)
+		joinTables, err = c.ConvertJoinTables(s.Types["Tag"])
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE TagPosts (
+  tagId STRING(MAX) NOT NULL,
+  postUserId STRING(MAX) NOT NULL,
+  postId STRING(MAX) NOT NULL,
+) PRIMARY KEY(tagId, postUserId, postId)`, joinTables[0].SQL())
+	})
+	t.Run("not a list", func(t *testing.T) {
+		c, err := converter.NewConverter(s, true, "", "", "", "")
+		require.NoError(t, err)
+		_, err = c.ConvertJoinTables(s.Types["Invalid"])
+		require.Error(t, err)
+	})
+}
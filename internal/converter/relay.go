@@ -0,0 +1,80 @@
+package converter
+
+import (
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// isRelayType reports whether def is a type of the Relay connection spec, which is not stored in a table:
+// PageInfo, *Connection with edges or pageInfo, and *Edge with node and cursor.
+func isRelayType(def *ast.Definition) bool {
+	if def.Kind != ast.Object {
+		return false
+	}
+	switch {
+	case def.Name == "PageInfo":
+		return def.Fields.ForName("hasNextPage") != nil
+	case strings.HasSuffix(def.Name, "Connection"):
+		return def.Fields.ForName("edges") != nil || def.Fields.ForName("pageInfo") != nil
+	case strings.HasSuffix(def.Name, "Edge"):
+		return def.Fields.ForName("node") != nil && def.Fields.ForName("cursor") != nil
+	}
+	return false
+}
+
+// connectionNode returns the node type of the connection type def, or nil if def is not a connection.
+func (c *Converter) connectionNode(def *ast.Definition) *ast.Definition {
+	if def == nil || !strings.HasSuffix(def.Name, "Connection") || !isRelayType(def) {
+		return nil
+	}
+	if f := def.Fields.ForName("nodes"); f != nil {
+		return c.schema.Types[f.Type.Name()]
+	}
+	if f := def.Fields.ForName("edges"); f != nil {
+		if edge := c.schema.Types[f.Type.Name()]; edge != nil {
+			if n := edge.Fields.ForName("node"); n != nil {
+				return c.schema.Types[n.Type.Name()]
+			}
+		}
+	}
+	return nil
+}
+
+// implementsNode reports whether def implements the Node interface of the Relay global object identification.
+func implementsNode(def *ast.Definition) bool {
+	for _, i := range def.Interfaces {
+		if i == "Node" {
+			return true
+		}
+	}
+	return false
+}
+
+// relayField reports whether f of def is not stored in the Relay mode:
+// a connection field, which is the inverse side of a one-to-many relation stored in the node table,
+// or the global id of Node if def has its own <Type>Id field.
+func (c *Converter) relayField(def *ast.Definition, f *ast.FieldDefinition) bool {
+	if !c.relay {
+		return false
+	}
+	if implementsNode(def) && f.Name == "id" {
+		for _, other := range def.Fields {
+			if NormalizeCase(other.Name) == NormalizeCase(def.Name+"Id") {
+				return true
+			}
+		}
+		return false
+	}
+	node := c.connectionNode(c.schema.Types[f.Type.Name()])
+	if node == nil {
+		return false
+	}
+	for _, nf := range node.Fields {
+		if nf.Type.Name() == def.Name {
+			return true
+		}
+	}
+	c.warnf(f.Position, "%s of %s is a connection of %s, but %s has no field referencing %s.", f.Name, def.Name, node.Name, node.Name, def.Name)
+	return true
+}
@@ -0,0 +1,41 @@
+package converter_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/nktks/gql-spansql/internal/converter"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/relay.gql
+var relayBody []byte
+
+func TestConverter_Relay(t *testing.T) {
+	s, err := loadGQL(relayBody)
+	require.NoError(t, err)
+	c, err := converter.New(s, converter.Options{Relay: true})
+	require.NoError(t, err)
+	sql, err := c.SpannerSQL()
+	require.NoError(t, err)
+	require.Equal(t, `CREATE TABLE Post (
+  id STRING(MAX) NOT NULL,
+  title STRING(MAX) NOT NULL,
+  authorId STRING(MAX) NOT NULL,
+) PRIMARY KEY(id);
+CREATE TABLE Reaction (
+  reactionId STRING(MAX) NOT NULL,
+) PRIMARY KEY(reactionId);
+CREATE TABLE User (
+  userId STRING(MAX) NOT NULL,
+  name STRING(MAX) NOT NULL,
+) PRIMARY KEY(userId);
+`, sql)
+	var ds []string
+	for _, d := range c.Diagnostics() {
+		ds = append(ds, d.Message)
+	}
+	require.Equal(t, []string{
+		"reactions of User is a connection of Reaction, but Reaction has no field referencing User.",
+	}, ds)
+}
@@ -0,0 +1,30 @@
+package converter
+
+import (
+	"io"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/nktks/gql-spansql/internal/spantype"
+)
+
+// Renderer renders a statement as SQL without the terminating semicolon.
+type Renderer func(spansql.DDLStmt) (string, error)
+
+// GoogleSQL renders stmt in the GoogleSQL dialect, including FLOAT32 which spansql can not render.
+func GoogleSQL(stmt spansql.DDLStmt) (string, error) {
+	return spantype.StmtSQL(stmt), nil
+}
+
+// Render writes stmts rendered by r to w, each terminated by ";\n".
+func Render(w io.Writer, stmts []spansql.DDLStmt, r Renderer) error {
+	for _, stmt := range stmts {
+		sql, err := r(stmt)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, sql+";\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package converter_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/nktks/gql-spansql/internal/converter"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+//go:embed testdata/strict.gql
+var strictBody []byte
+
+func TestConverter_Strict(t *testing.T) {
+	s, err := gqlparser.LoadSchema(converter.Directives, &ast.Source{Name: "strict.gql", Input: string(strictBody)})
+	require.NoError(t, err)
+	t.Run("mapped", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{Strict: true})
+		require.NoError(t, err)
+		_, err = c.ConvertDefinition(s.Types["Post"])
+		require.NoError(t, err)
+	})
+	t.Run("unmapped scalar", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{Strict: true})
+		require.NoError(t, err)
+		_, err = c.ConvertDefinition(s.Types["Tagged"])
+		require.EqualError(t, err, "strict.gql:22:3: tags: scalar type Tag declared at strict.gql:35:8 is not mapped to a spanner type.")
+		c, err = converter.New(s, converter.Options{Strict: true, ScalarTypes: map[string]string{"Tag": "STRING(64)"}})
+		require.NoError(t, err)
+		_, err = c.ConvertDefinition(s.Types["Tagged"])
+		require.NoError(t, err)
+	})
+	t.Run("relation without pk", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{Strict: true})
+		require.NoError(t, err)
+		_, err = c.ConvertDefinition(s.Types["Comment"])
+		require.EqualError(t, err, "strict.gql:17:3: anonymous: primary key of the relation type Anonymous declared at strict.gql:25:6 can not be detected.")
+	})
+	t.Run("not strict", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{})
+		require.NoError(t, err)
+		_, err = c.SpannerSQL()
+		require.NoError(t, err)
+	})
+	t.Run("loose and strict", func(t *testing.T) {
+		_, err := converter.New(s, converter.Options{Loose: true, Strict: true})
+		require.Error(t, err)
+	})
+}
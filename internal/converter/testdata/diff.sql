@@ -0,0 +1,26 @@
+CREATE TABLE Legacy (
+  legacyId STRING(MAX) NOT NULL,
+) PRIMARY KEY(legacyId);
+
+CREATE TABLE LegacyChild (
+  legacyId STRING(MAX) NOT NULL,
+  legacyChildId STRING(MAX) NOT NULL,
+) PRIMARY KEY(legacyId, legacyChildId),
+  INTERLEAVE IN PARENT Legacy ON DELETE CASCADE;
+
+CREATE TABLE User (
+  userId STRING(MAX) NOT NULL,
+  name STRING(256),
+  email STRING(MAX) NOT NULL,
+  legacyId STRING(MAX),
+  teamId STRING(MAX) NOT NULL,
+  CONSTRAINT FK_User_legacy FOREIGN KEY (legacyId) REFERENCES Legacy (legacyId) ON DELETE NO ACTION,
+) PRIMARY KEY(userId);
+
+CREATE INDEX UserByEmail ON User(email);
+CREATE INDEX UserByName ON User(name);
+
+CREATE TABLE Team (
+  teamId STRING(MAX) NOT NULL,
+  name STRING(MAX) NOT NULL,
+) PRIMARY KEY(teamId);
@@ -0,0 +1,102 @@
+package converter
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/nktks/gql-spansql/internal/spantype"
+)
+
+// defaultScalarTypes is the spanner types of the well-known custom scalars without annotation.
+var defaultScalarTypes = map[string]string{
+	"Time":      "TIMESTAMP",
+	"TimeStamp": "TIMESTAMP",
+	"Timestamp": "TIMESTAMP",
+	"Date":      "DATE",
+}
+
+var (
+	sizedTypeRe = regexp.MustCompile(`^(STRING|BYTES)\s*\(\s*(MAX|[0-9]+)\s*\)$`)
+	arrayTypeRe = regexp.MustCompile(`(?i)^ARRAY\s*<(.*)>$`)
+)
+
+// parseSpannerType parses a spanner type expression such as STRING(256), BYTES(MAX), NUMERIC, JSON or ARRAY<FLOAT32>.
+// STRING and BYTES without length are STRING(MAX) and BYTES(MAX).
+// the GraphQL type names accepted by "SpannerType:" descriptions, e.g. Int, are also accepted.
+func parseSpannerType(s string) (spansql.Type, error) {
+	if m := arrayTypeRe.FindStringSubmatch(strings.TrimSpace(s)); m != nil {
+		t, err := parseSpannerType(m[1])
+		if err != nil {
+			return spansql.Type{}, err
+		}
+		if t.Array {
+			return spansql.Type{}, fmt.Errorf("spanner type %s is not supported. ARRAY of ARRAY is not allowed.", s)
+		}
+		t.Array = true
+		return t, nil
+	}
+	expr := strings.ToUpper(strings.TrimSpace(s))
+	if m := sizedTypeRe.FindStringSubmatch(expr); m != nil {
+		t := spansql.Type{Base: spansql.String, Len: math.MaxInt64}
+		if m[1] == "BYTES" {
+			t.Base = spansql.Bytes
+		}
+		if m[2] != "MAX" {
+			l, err := strconv.ParseInt(m[2], 10, 64)
+			if err != nil || l <= 0 {
+				return spansql.Type{}, fmt.Errorf("length of spanner type %s is invalid.", s)
+			}
+			t.Len = l
+		}
+		return t, nil
+	}
+	switch expr {
+	case "BOOL":
+		return spansql.Type{Base: spansql.Bool}, nil
+	case "INT64":
+		return spansql.Type{Base: spansql.Int64}, nil
+	case "FLOAT64":
+		return spansql.Type{Base: spansql.Float64}, nil
+	case "FLOAT32":
+		return spantype.Float32(), nil
+	case "NUMERIC":
+		return spansql.Type{Base: spansql.Numeric}, nil
+	case "STRING":
+		return spansql.Type{Base: spansql.String, Len: math.MaxInt64}, nil
+	case "BYTES":
+		return spansql.Type{Base: spansql.Bytes, Len: math.MaxInt64}, nil
+	case "DATE":
+		return spansql.Type{Base: spansql.Date}, nil
+	case "TIMESTAMP":
+		return spansql.Type{Base: spansql.Timestamp}, nil
+	case "JSON":
+		return spansql.Type{Base: spansql.JSON}, nil
+	}
+	b, err := typeBaseOf(strings.TrimSpace(s))
+	if err != nil {
+		return spansql.Type{}, err
+	}
+	t := spansql.Type{Base: b}
+	if b == spansql.String {
+		t.Len = math.MaxInt64
+	}
+	return t, nil
+}
+
+// ParseScalarTypes parses the mappings of custom scalars to spanner types in the form of Name=TYPE.
+func ParseScalarTypes(mappings []string) (map[string]string, error) {
+	types := map[string]string{}
+	for _, m := range mappings {
+		name, t, ok := strings.Cut(m, "=")
+		name, t = strings.TrimSpace(name), strings.TrimSpace(t)
+		if !ok || name == "" || t == "" {
+			return nil, fmt.Errorf("scalar mapping %s must be Name=TYPE.", m)
+		}
+		types[name] = t
+	}
+	return types, nil
+}
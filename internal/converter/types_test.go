@@ -0,0 +1,124 @@
+package converter_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/nktks/gql-spansql/internal/converter"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/scalar_types.gql
+var scalarTypesBody []byte
+
+func TestConverter_ScalarTypes(t *testing.T) {
+	s, err := loadGQL(scalarTypesBody)
+	require.NoError(t, err)
+	t.Run("mapped", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{
+			ScalarTypes: map[string]string{
+				"Email":      "STRING(256)",
+				"Upload":     "BYTES(MAX)",
+				"Decimal":    "NUMERIC",
+				"JSONObject": "JSON",
+				"DateTime":   "TIMESTAMP",
+				"Tag":        "string(64)",
+				"Score":      "FLOAT32",
+			},
+		})
+		require.NoError(t, err)
+		createTable, err := c.ConvertDefinition(s.Types["User"])
+		require.NoError(t, err)
+		sql, err := converter.GoogleSQL(createTable)
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE User (
+  id STRING(MAX) NOT NULL,
+  email STRING(256) NOT NULL,
+  avatar BYTES(MAX),
+  balance NUMERIC NOT NULL,
+  settings JSON,
+  birthday DATE,
+  lastSeen TIMESTAMP,
+  loginAt INT64 NOT NULL,
+  tags ARRAY<STRING(64)> NOT NULL,
+  code STRING(8),
+  digest BYTES(32),
+  score FLOAT32,
+) PRIMARY KEY(id)`, sql)
+	})
+	t.Run("not mapped", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{})
+		require.NoError(t, err)
+		createTable, err := c.ConvertDefinition(s.Types["User"])
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE User (
+  id STRING(MAX) NOT NULL,
+  email STRING(MAX) NOT NULL,
+  avatar STRING(MAX),
+  balance STRING(MAX) NOT NULL,
+  settings STRING(MAX),
+  birthday DATE,
+  lastSeen STRING(MAX),
+  loginAt INT64 NOT NULL,
+  tags ARRAY<STRING(MAX)> NOT NULL,
+  code STRING(8),
+  digest BYTES(32),
+  score STRING(MAX),
+) PRIMARY KEY(id)`, createTable.SQL())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, typ := range []string{"STRING(0)", "VARCHAR", "FLOAT"} {
+			_, err := converter.New(s, converter.Options{ScalarTypes: map[string]string{"Email": typ}})
+			require.Error(t, err, typ)
+		}
+	})
+}
+
+func TestParseScalarTypes(t *testing.T) {
+	types, err := converter.ParseScalarTypes([]string{"DateTime=TIMESTAMP", " Email = STRING(256) "})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"DateTime": "TIMESTAMP", "Email": "STRING(256)"}, types)
+	_, err = converter.ParseScalarTypes([]string{"DateTime"})
+	require.Error(t, err)
+}
+
+//go:embed testdata/spanner_types.gql
+var spannerTypesBody []byte
+
+func TestConverter_SpannerTypes(t *testing.T) {
+	s, err := loadGQL(spannerTypesBody)
+	require.NoError(t, err)
+	c, err := converter.NewConverter(s, false, "", "", "", "")
+	require.NoError(t, err)
+	t.Run("type expressions", func(t *testing.T) {
+		createTable, err := c.ConvertDefinition(s.Types["Measurement"])
+		require.NoError(t, err)
+		sql, err := converter.GoogleSQL(createTable)
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE Measurement (
+  id STRING(36) NOT NULL,
+  values ARRAY<FLOAT64> NOT NULL,
+  vector ARRAY<FLOAT64>,
+  blobs ARRAY<BYTES(1024)>,
+  price NUMERIC NOT NULL,
+  attrs JSON,
+  counts ARRAY<INT64>,
+  ratio FLOAT32 NOT NULL,
+  embedding ARRAY<FLOAT32>,
+) PRIMARY KEY(id)`, sql)
+	})
+	t.Run("relation keeps length", func(t *testing.T) {
+		createTable, err := c.ConvertDefinition(s.Types["Sample"])
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE Sample (
+  sampleId STRING(MAX) NOT NULL,
+  measurementId STRING(36) NOT NULL,
+) PRIMARY KEY(sampleId)`, createTable.SQL())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, name := range []string{"InvalidNested", "InvalidArray", "InvalidLength", "InvalidInterval", "InvalidBigInt", "InvalidUUIDString"} {
+			_, err := c.ConvertDefinition(s.Types[name])
+			require.Error(t, err, name)
+		}
+	})
+}
@@ -0,0 +1,205 @@
+package converter
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/nktks/gql-spansql/internal/spantype"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const (
+	discriminatorPolymorphism = "DISCRIMINATOR"
+	columnPerTypePolymorphism = "COLUMN_PER_TYPE"
+)
+
+// unionOf returns the definition which the polymorphic reference f refers to, or nil if f is not a polymorphic reference:
+// a union, or an interface whose implementing types are stored in their own tables.
+func (c *Converter) unionOf(f *ast.FieldDefinition) *ast.Definition {
+	if isJSON(f) {
+		return nil
+	}
+	def, ok := c.schema.Types[f.Type.Name()]
+	if !ok || (def.Kind != ast.Union && c.inheritance(def) != tablePerTypeInheritance) {
+		return nil
+	}
+	return def
+}
+
+// polymorphism returns how the reference of the union field f to u is stored,
+// by @polymorphic of f or u, or Options.Unions. it is DISCRIMINATOR by default.
+func (c *Converter) polymorphism(f *ast.FieldDefinition, u *ast.Definition) string {
+	for _, d := range []*ast.Directive{f.Directives.ForName(polymorphicDirective), u.Directives.ForName(polymorphicDirective)} {
+		if d == nil {
+			continue
+		}
+		if s, ok := stringArg(d, "strategy"); ok {
+			return s
+		}
+	}
+	if s, ok := c.unions[u.Name]; ok {
+		return s
+	}
+	return discriminatorPolymorphism
+}
+
+// unionMembers returns the definitions of the tables which the members or the implementing types of u are stored in.
+func (c *Converter) unionMembers(u *ast.Definition) []*ast.Definition {
+	var members []*ast.Definition
+	seen := map[string]bool{}
+	for _, m := range c.schema.GetPossibleTypes(u) {
+		if i := c.singleTableOf(m); i != nil {
+			m = i
+		}
+		if seen[m.Name] {
+			continue
+		}
+		seen[m.Name] = true
+		members = append(members, m)
+	}
+	return members
+}
+
+// unionColumns converts the union field f to the columns of the polymorphic reference.
+// with DISCRIMINATOR, they are <field>Type of the type name and <field>Id of the primary key, whose type must be common to the members.
+// with COLUMN_PER_TYPE, they are nullable <field><Member>Id columns per member.
+func (c *Converter) unionColumns(f *ast.FieldDefinition, u *ast.Definition) ([]spansql.ColumnDef, error) {
+	if c.polymorphism(f, u) == columnPerTypePolymorphism {
+		_, cols, err := c.memberColumns(f, u)
+		if err != nil {
+			return nil, err
+		}
+		var flat []spansql.ColumnDef
+		for _, mcols := range cols {
+			flat = append(flat, mcols...)
+		}
+		return flat, nil
+	}
+	if err := c.checkUnionField(f, u); err != nil {
+		return nil, err
+	}
+	var (
+		idType spansql.Type
+		from   *ast.Definition
+	)
+	for _, m := range c.unionMembers(u) {
+		_, kcols, err := c.keyColumns(m)
+		if err != nil {
+			return nil, err
+		}
+		if len(kcols) > 1 {
+			return nil, fmt.Errorf("%s: %s of %s has multiple primary key columns.", f.Name, m.Name, polymorphicName(u))
+		}
+		t := kcols[0].Type
+		if from == nil {
+			idType, from = t, m
+			continue
+		}
+		if t.Base != idType.Base || t.Array != idType.Array || t.ProtoRef != idType.ProtoRef {
+			return nil, fmt.Errorf("%s: primary keys of the members of %s are incompatible. %s of %s and %s of %s.", f.Name, polymorphicName(u), spantype.SQL(idType), from.Name, spantype.SQL(t), m.Name)
+		}
+		if t.Len > idType.Len {
+			idType.Len = t.Len
+		}
+	}
+	fieldCase := c.relationFieldCase(f)
+	return []spansql.ColumnDef{
+		{
+			Name:    spansql.ID(ConvertCase(f.Name+"Type", fieldCase)),
+			Type:    spansql.Type{Base: spansql.String, Len: math.MaxInt64},
+			NotNull: f.Type.NonNull,
+		},
+		{
+			Name:    spansql.ID(ConvertCase(f.Name+"Id", fieldCase)),
+			Type:    idType,
+			NotNull: f.Type.NonNull,
+		},
+	}, nil
+}
+
+// memberColumns returns the members of u and their nullable key columns of the union field f stored with COLUMN_PER_TYPE.
+func (c *Converter) memberColumns(f *ast.FieldDefinition, u *ast.Definition) ([]*ast.Definition, [][]spansql.ColumnDef, error) {
+	if err := c.checkUnionField(f, u); err != nil {
+		return nil, nil, err
+	}
+	members := c.unionMembers(u)
+	cols := make([][]spansql.ColumnDef, 0, len(members))
+	for _, m := range members {
+		_, kcols, err := c.keyColumns(m)
+		if err != nil {
+			return nil, nil, err
+		}
+		member := *f
+		member.Name = f.Name + m.Name
+		member.Type = ast.NamedType(m.Name, nil)
+		member.Directives = nil
+		member.Description = ""
+		mcols, err := c.relationColumns(&member, m, kcols)
+		if err != nil {
+			return nil, nil, err
+		}
+		cols = append(cols, mcols)
+	}
+	return members, cols, nil
+}
+
+// polymorphicName returns the kind and the name of u for messages, e.g. union Owner.
+func polymorphicName(u *ast.Definition) string {
+	return strings.ToLower(string(u.Kind)) + " " + u.Name
+}
+
+func (c *Converter) checkUnionField(f *ast.FieldDefinition, u *ast.Definition) error {
+	if f.Type.Elem != nil {
+		return fmt.Errorf("%s: list of %s is not supported.", f.Name, polymorphicName(u))
+	}
+	if f.Directives.ForName(spannerColumnDirective) != nil || spanColumnRe.MatchString(f.Description) {
+		return fmt.Errorf("%s: column name can not be specified to the reference to %s.", f.Name, polymorphicName(u))
+	}
+	return nil
+}
+
+// unionForeignKeys returns FOREIGN KEY constraints of the union field f to its members.
+// they are only available with COLUMN_PER_TYPE, as the column of DISCRIMINATOR refers to multiple tables.
+func (c *Converter) unionForeignKeys(f *ast.FieldDefinition, u *ast.Definition, d *ast.Directive, sc *spansql.CreateTable) ([]spansql.TableConstraint, error) {
+	if c.polymorphism(f, u) != columnPerTypePolymorphism {
+		if d != nil {
+			return nil, fmt.Errorf("%s: @%s is only allowed on the reference to %s stored with %s.", f.Name, foreignKeyDirective, polymorphicName(u), columnPerTypePolymorphism)
+		}
+		return nil, nil
+	}
+	members, cols, err := c.memberColumns(f, u)
+	if err != nil {
+		return nil, err
+	}
+	var constraints []spansql.TableConstraint
+	for i, m := range members {
+		if c.excluded(m) {
+			continue
+		}
+		kp, _, err := c.keyColumns(m)
+		if err != nil {
+			return nil, err
+		}
+		fk := spansql.ForeignKey{
+			RefTable: spansql.ID(ConvertCase(m.Name, c.tableCase)),
+		}
+		for j := range cols[i] {
+			fk.Columns = append(fk.Columns, cols[i][j].Name)
+			fk.RefColumns = append(fk.RefColumns, kp[j].Column)
+		}
+		name := fmt.Sprintf("FK_%s_%s", sc.Name, ConvertCase(f.Name+m.Name, c.columnCase))
+		if d != nil {
+			fk.OnDelete = onDeleteArg(d)
+			if n, ok := stringArg(d, "name"); ok && n != "" {
+				name = n + "_" + m.Name
+			}
+		}
+		constraints = append(constraints, spansql.TableConstraint{
+			Name:       spansql.ID(name),
+			Constraint: fk,
+		})
+	}
+	return constraints, nil
+}
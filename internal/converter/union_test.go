@@ -0,0 +1,103 @@
+package converter_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/nktks/gql-spansql/internal/converter"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/union.gql
+var unionBody []byte
+
+func TestConverter_Union(t *testing.T) {
+	s, err := loadGQL(unionBody)
+	require.NoError(t, err)
+	t.Run("strategies", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{})
+		require.NoError(t, err)
+		sql, err := c.SpannerSQL()
+		require.NoError(t, err)
+		require.Equal(t, `CREATE TABLE Team (
+  teamId STRING(36) NOT NULL,
+) PRIMARY KEY(teamId);
+CREATE TABLE User (
+  userId STRING(MAX) NOT NULL,
+) PRIMARY KEY(userId);
+CREATE TABLE Repository (
+  repositoryId STRING(MAX) NOT NULL,
+  ownerType STRING(MAX) NOT NULL,
+  ownerId STRING(MAX) NOT NULL,
+  targetUserId STRING(MAX),
+  targetTeamId STRING(36),
+  CONSTRAINT FK_Repository_targetUser FOREIGN KEY (targetUserId) REFERENCES User (userId) ON DELETE CASCADE,
+  CONSTRAINT FK_Repository_targetTeam FOREIGN KEY (targetTeamId) REFERENCES Team (teamId) ON DELETE CASCADE,
+) PRIMARY KEY(repositoryId);
+`, sql)
+		require.Empty(t, c.Diagnostics())
+	})
+	t.Run("column per type by options", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{Unions: map[string]string{"Owner": "COLUMN_PER_TYPE"}, ExcludeTypes: []string{"Team"}})
+		require.NoError(t, err)
+		sql, err := c.SpannerSQL()
+		require.NoError(t, err)
+		require.Contains(t, sql, `  ownerUserId STRING(MAX),
+  ownerTeamId STRING(36),
+`)
+	})
+	t.Run("incompatible", func(t *testing.T) {
+		s, err := loadGQL([]byte(`union Owner = User | Team
+
+type User {
+  userId: ID!
+}
+
+type Team {
+  teamId: Int!
+}
+
+type Repository {
+  repositoryId: ID!
+  owner: Owner
+}
+`))
+		require.NoError(t, err)
+		c, err := converter.New(s, converter.Options{})
+		require.NoError(t, err)
+		_, err = c.SpannerSQL()
+		require.EqualError(t, err, "-:13:3: owner: primary keys of the members of union Owner are incompatible. STRING(MAX) of User and INT64 of Team.")
+	})
+	t.Run("foreign key with discriminator", func(t *testing.T) {
+		c, err := converter.New(s, converter.Options{Unions: map[string]string{"Owner": "DISCRIMINATOR"}, ForeignKeys: true})
+		require.NoError(t, err)
+		_, err = c.SpannerSQL()
+		require.NoError(t, err)
+		_, err = converter.New(s, converter.Options{Unions: map[string]string{"Owner": "JOINED"}})
+		require.EqualError(t, err, "polymorphism strategy JOINED of Owner not found.")
+	})
+	t.Run("index and change stream", func(t *testing.T) {
+		s, err := loadGQL([]byte(`union Owner = User | Team
+
+type User {
+  userId: ID!
+}
+
+type Team {
+  teamId: ID!
+}
+
+type Doc @changeStream(name: "DocOwners", columns: ["owner"]) {
+  docId: ID!
+  owner: Owner @index
+}
+`))
+		require.NoError(t, err)
+		c, err := converter.New(s, converter.Options{})
+		require.NoError(t, err)
+		sql, err := c.SpannerSQL()
+		require.NoError(t, err)
+		require.Contains(t, sql, "CREATE INDEX DocByOwner ON Doc(ownerType, ownerId);\n")
+		require.Contains(t, sql, "CREATE CHANGE STREAM DocOwners FOR Doc(ownerType, ownerId);\n")
+	})
+}
@@ -0,0 +1,149 @@
+// Package gostruct renders spansql tables as Go structs to be read by spanner.Row.ToStruct.
+package gostruct
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/iancoleman/strcase"
+	"github.com/nktks/gql-spansql/internal/spantype"
+)
+
+const (
+	spannerPkg = "cloud.google.com/go/spanner"
+	civilPkg   = "cloud.google.com/go/civil"
+	bigPkg     = "math/big"
+	timePkg    = "time"
+)
+
+// initialisms are the words which are upper-cased as a whole in Go names.
+var initialisms = map[string]bool{
+	"acl": true, "api": true, "ascii": true, "cpu": true, "css": true, "dns": true, "eof": true,
+	"guid": true, "html": true, "http": true, "https": true, "id": true, "ip": true, "json": true,
+	"qps": true, "ram": true, "rpc": true, "sql": true, "ssh": true, "tcp": true, "tls": true,
+	"ttl": true, "udp": true, "ui": true, "uid": true, "uri": true, "url": true, "utf8": true,
+	"uuid": true, "xml": true,
+}
+
+// Name converts s to an exported Go name, e.g. user_id and userId to UserID.
+func Name(s string) string {
+	name := ""
+	for _, w := range strings.Split(strcase.ToSnake(s), "_") {
+		if w == "" {
+			continue
+		}
+		if initialisms[w] {
+			name += strings.ToUpper(w)
+			continue
+		}
+		name += strings.ToUpper(w[:1]) + w[1:]
+	}
+	return name
+}
+
+type goType struct {
+	name string
+	pkg  string
+}
+
+// typeOf returns the Go type of a column.
+// nullable columns are mapped to the spanner.Null* types,
+// and arrays are mapped to slices which are nil for NULL.
+func typeOf(t spansql.Type, notNull bool) (goType, error) {
+	if t.Array {
+		notNull = true
+	}
+	var gt goType
+	switch t.Base {
+	case spansql.Bool:
+		gt = nullable(notNull, "bool", "", "spanner.NullBool")
+	case spansql.Int64:
+		gt = nullable(notNull, "int64", "", "spanner.NullInt64")
+	case spansql.Float64:
+		gt = nullable(notNull, "float64", "", "spanner.NullFloat64")
+		if spantype.IsFloat32(t) {
+			gt = nullable(notNull, "float32", "", "spanner.NullFloat32")
+		}
+	case spansql.Numeric:
+		gt = nullable(notNull, "big.Rat", bigPkg, "spanner.NullNumeric")
+	case spansql.String:
+		gt = nullable(notNull, "string", "", "spanner.NullString")
+	case spansql.Bytes:
+		gt = goType{name: "[]byte"}
+	case spansql.Date:
+		gt = nullable(notNull, "civil.Date", civilPkg, "spanner.NullDate")
+	case spansql.Timestamp:
+		gt = nullable(notNull, "time.Time", timePkg, "spanner.NullTime")
+	case spansql.JSON:
+		gt = goType{name: "spanner.NullJSON", pkg: spannerPkg}
+	default:
+		return goType{}, fmt.Errorf("type %s is not supported in go struct.", t.SQL())
+	}
+	if t.Array {
+		gt.name = "[]" + gt.name
+	}
+	return gt, nil
+}
+
+func nullable(notNull bool, name, pkg, null string) goType {
+	if notNull {
+		return goType{name: name, pkg: pkg}
+	}
+	return goType{name: null, pkg: spannerPkg}
+}
+
+// Generate renders tables as Go structs of package pkg.
+func Generate(pkg string, tables []*spansql.CreateTable) (string, error) {
+	imports := map[string]bool{}
+	var body bytes.Buffer
+	for _, ct := range tables {
+		fmt.Fprintf(&body, "\n// %s is a row of %s.\n", Name(string(ct.Name)), ct.Name)
+		fmt.Fprintf(&body, "type %s struct {\n", Name(string(ct.Name)))
+		for _, cd := range ct.Columns {
+			gt, err := typeOf(cd.Type, cd.NotNull)
+			if err != nil {
+				return "", fmt.Errorf("%s.%s: %w", ct.Name, cd.Name, err)
+			}
+			if gt.pkg != "" {
+				imports[gt.pkg] = true
+			}
+			fmt.Fprintf(&body, "%s %s `spanner:\"%s\"`\n", Name(string(cd.Name)), gt.name, cd.Name)
+		}
+		body.WriteString("}\n")
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by gql-spansql. DO NOT EDIT.\n\npackage %s\n", pkg)
+	if len(imports) > 0 {
+		paths := make([]string, 0, len(imports))
+		for p := range imports {
+			paths = append(paths, p)
+		}
+		// the standard packages are grouped before the others.
+		sort.Slice(paths, func(i, j int) bool {
+			si, sj := !strings.Contains(paths[i], "."), !strings.Contains(paths[j], ".")
+			if si != sj {
+				return si
+			}
+			return paths[i] < paths[j]
+		})
+		src.WriteString("\nimport (\n")
+		for i, p := range paths {
+			if i > 0 && strings.Contains(p, ".") && !strings.Contains(paths[i-1], ".") {
+				src.WriteString("\n")
+			}
+			fmt.Fprintf(&src, "%q\n", p)
+		}
+		src.WriteString(")\n")
+	}
+	src.Write(body.Bytes())
+	b, err := format.Source(src.Bytes())
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,81 @@
+package gostruct_test
+
+import (
+	"testing"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/nktks/gql-spansql/internal/gostruct"
+	"github.com/nktks/gql-spansql/internal/spantype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestName(t *testing.T) {
+	for s, want := range map[string]string{
+		"user_id":   "UserID",
+		"userId":    "UserID",
+		"UserId":    "UserID",
+		"name":      "Name",
+		"image_url": "ImageURL",
+		"UserPosts": "UserPosts",
+	} {
+		require.Equal(t, want, gostruct.Name(s), s)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	ddl, err := spansql.ParseDDL("gostruct.sql", `
+CREATE TABLE user_item (
+  user_id STRING(MAX) NOT NULL,
+  name STRING(MAX),
+  count INT64 NOT NULL,
+  score FLOAT64,
+  active BOOL,
+  price NUMERIC,
+  birthday DATE,
+  tags ARRAY<STRING(MAX)>,
+  data BYTES(MAX),
+  meta JSON,
+  created_at TIMESTAMP NOT NULL,
+  updated_at TIMESTAMP,
+) PRIMARY KEY(user_id);
+`)
+	require.NoError(t, err)
+	var tables []*spansql.CreateTable
+	for _, stmt := range ddl.List {
+		tables = append(tables, stmt.(*spansql.CreateTable))
+	}
+	// spansql can not parse FLOAT32.
+	tables[0].Columns = append(tables[0].Columns,
+		spansql.ColumnDef{Name: "ratio", Type: spantype.Float32(), NotNull: true},
+		spansql.ColumnDef{Name: "weight", Type: spantype.Float32()},
+	)
+	src, err := gostruct.Generate("model", tables)
+	require.NoError(t, err)
+	require.Equal(t, "// Code generated by gql-spansql. DO NOT EDIT.\n"+`
+package model
+
+import (
+	"time"
+
+	"cloud.google.com/go/spanner"
+)
+
+// UserItem is a row of user_item.
+type UserItem struct {
+	UserID    string              `+"`spanner:\"user_id\"`"+`
+	Name      spanner.NullString  `+"`spanner:\"name\"`"+`
+	Count     int64               `+"`spanner:\"count\"`"+`
+	Score     spanner.NullFloat64 `+"`spanner:\"score\"`"+`
+	Active    spanner.NullBool    `+"`spanner:\"active\"`"+`
+	Price     spanner.NullNumeric `+"`spanner:\"price\"`"+`
+	Birthday  spanner.NullDate    `+"`spanner:\"birthday\"`"+`
+	Tags      []string            `+"`spanner:\"tags\"`"+`
+	Data      []byte              `+"`spanner:\"data\"`"+`
+	Meta      spanner.NullJSON    `+"`spanner:\"meta\"`"+`
+	CreatedAt time.Time           `+"`spanner:\"created_at\"`"+`
+	UpdatedAt spanner.NullTime    `+"`spanner:\"updated_at\"`"+`
+	Ratio     float32             `+"`spanner:\"ratio\"`"+`
+	Weight    spanner.NullFloat32 `+"`spanner:\"weight\"`"+`
+}
+`, src)
+}
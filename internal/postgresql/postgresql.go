@@ -0,0 +1,337 @@
+// Package postgresql renders spansql statements in the PostgreSQL dialect of spanner.
+// https://cloud.google.com/spanner/docs/reference/postgresql/data-definition-language
+package postgresql
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/nktks/gql-spansql/internal/spantype"
+)
+
+var plainIdentRe = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// reserved is the reserved keywords of PostgreSQL.
+// https://www.postgresql.org/docs/current/sql-keywords-appendix.html
+var reserved = map[string]bool{
+	"all": true, "analyse": true, "analyze": true, "and": true, "any": true, "array": true, "as": true,
+	"asc": true, "asymmetric": true, "both": true, "case": true, "cast": true, "check": true, "collate": true,
+	"column": true, "constraint": true, "create": true, "current_catalog": true, "current_date": true,
+	"current_role": true, "current_time": true, "current_timestamp": true, "current_user": true,
+	"default": true, "deferrable": true, "desc": true, "distinct": true, "do": true, "else": true, "end": true,
+	"except": true, "false": true, "fetch": true, "for": true, "foreign": true, "from": true, "grant": true,
+	"group": true, "having": true, "in": true, "initially": true, "intersect": true, "into": true,
+	"lateral": true, "leading": true, "limit": true, "localtime": true, "localtimestamp": true, "not": true,
+	"null": true, "offset": true, "on": true, "only": true, "or": true, "order": true, "placing": true,
+	"primary": true, "references": true, "returning": true, "select": true, "session_user": true,
+	"some": true, "symmetric": true, "system_user": true, "table": true, "then": true, "to": true,
+	"trailing": true, "true": true, "union": true, "unique": true, "user": true, "using": true,
+	"variadic": true, "when": true, "where": true, "window": true, "with": true,
+}
+
+// ID renders id, quoting it if it is not folded to itself by PostgreSQL.
+func ID(id spansql.ID) string {
+	s := string(id)
+	if plainIdentRe.MatchString(s) && !reserved[s] {
+		return s
+	}
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+func idList(ids []spansql.ID) string {
+	ss := make([]string, 0, len(ids))
+	for _, id := range ids {
+		ss = append(ss, ID(id))
+	}
+	return strings.Join(ss, ", ")
+}
+
+// Type renders t as a PostgreSQL type.
+func Type(t spansql.Type) (string, error) {
+	var s string
+	switch t.Base {
+	case spansql.Bool:
+		s = "bool"
+	case spansql.Int64:
+		s = "bigint"
+	case spansql.Float64:
+		s = "double precision"
+		if spantype.IsFloat32(t) {
+			s = "real"
+		}
+	case spansql.Numeric:
+		s = "numeric"
+	case spansql.String:
+		s = "varchar"
+		if t.Len > 0 && t.Len != spansql.MaxLen {
+			s += "(" + strconv.FormatInt(t.Len, 10) + ")"
+		}
+	case spansql.Bytes:
+		s = "bytea"
+	case spansql.Date:
+		s = "date"
+	case spansql.Timestamp:
+		s = "timestamptz"
+	case spansql.JSON:
+		s = "jsonb"
+	default:
+		return "", fmt.Errorf("type %s is not supported in postgresql dialect.", t.SQL())
+	}
+	if t.Array {
+		s += "[]"
+	}
+	return s, nil
+}
+
+func column(cd spansql.ColumnDef) (string, error) {
+	t, err := Type(cd.Type)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", cd.Name, err)
+	}
+	s := ID(cd.Name) + " " + t
+	if cd.NotNull {
+		s += " NOT NULL"
+	}
+	return s, nil
+}
+
+func onDelete(od spansql.OnDelete) string {
+	if od == spansql.CascadeOnDelete {
+		return "CASCADE"
+	}
+	return "NO ACTION"
+}
+
+func constraint(tc spansql.TableConstraint) (string, error) {
+	var s string
+	if tc.Name != "" {
+		s = "CONSTRAINT " + ID(tc.Name) + " "
+	}
+	switch c := tc.Constraint.(type) {
+	case spansql.ForeignKey:
+		s += "FOREIGN KEY (" + idList(c.Columns) + ") REFERENCES " + ID(c.RefTable) + " (" + idList(c.RefColumns) + ") ON DELETE " + onDelete(c.OnDelete)
+	case spansql.Check:
+		e, err := expr(c.Expr)
+		if err != nil {
+			return "", err
+		}
+		s += "CHECK (" + e + ")"
+	default:
+		return "", fmt.Errorf("constraint %s is not supported in postgresql dialect.", tc.SQL())
+	}
+	return s, nil
+}
+
+// expr renders the expression e of a CHECK constraint.
+// string literals are quoted by single quotes, as double quotes are for identifiers in PostgreSQL.
+func expr(e spansql.Expr) (string, error) {
+	switch e := e.(type) {
+	case spansql.ID:
+		return ID(e), nil
+	case spansql.StringLiteral:
+		return "'" + strings.ReplaceAll(string(e), "'", "''") + "'", nil
+	case spansql.InOp:
+		if e.Unnest {
+			break
+		}
+		lhs, err := expr(e.LHS)
+		if err != nil {
+			return "", err
+		}
+		rhs := make([]string, 0, len(e.RHS))
+		for _, r := range e.RHS {
+			s, err := expr(r)
+			if err != nil {
+				return "", err
+			}
+			rhs = append(rhs, s)
+		}
+		op := " IN ("
+		if e.Neg {
+			op = " NOT IN ("
+		}
+		return lhs + op + strings.Join(rhs, ", ") + ")", nil
+	}
+	return "", fmt.Errorf("expression %s is not supported in postgresql dialect.", e.SQL())
+}
+
+// CreateTable renders ct. the primary key is declared in the column list.
+func CreateTable(ct *spansql.CreateTable) (string, error) {
+	s := "CREATE TABLE " + ID(ct.Name) + " (\n"
+	for _, cd := range ct.Columns {
+		col, err := column(cd)
+		if err != nil {
+			return "", fmt.Errorf("%s.%w", ct.Name, err)
+		}
+		s += "  " + col + ",\n"
+	}
+	for _, tc := range ct.Constraints {
+		c, err := constraint(tc)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", ct.Name, err)
+		}
+		s += "  " + c + ",\n"
+	}
+	keys := make([]spansql.ID, 0, len(ct.PrimaryKey))
+	for _, kp := range ct.PrimaryKey {
+		if kp.Desc {
+			return "", fmt.Errorf("%s: descending primary key %s is not supported in postgresql dialect.", ct.Name, kp.Column)
+		}
+		keys = append(keys, kp.Column)
+	}
+	s += "  PRIMARY KEY (" + idList(keys) + ")\n)"
+	if il := ct.Interleave; il != nil {
+		s += " INTERLEAVE IN PARENT " + ID(il.Parent) + " ON DELETE " + onDelete(il.OnDelete)
+	}
+	return s, nil
+}
+
+// CreateIndex renders ci. STORING and NULL_FILTERED are rendered as INCLUDE and WHERE ... IS NOT NULL.
+func CreateIndex(ci *spansql.CreateIndex) (string, error) {
+	s := "CREATE"
+	if ci.Unique {
+		s += " UNIQUE"
+	}
+	s += " INDEX " + ID(ci.Name) + " ON " + ID(ci.Table) + " ("
+	cols := make([]string, 0, len(ci.Columns))
+	for _, kp := range ci.Columns {
+		col := ID(kp.Column)
+		if kp.Desc {
+			col += " DESC"
+		}
+		cols = append(cols, col)
+	}
+	s += strings.Join(cols, ", ") + ")"
+	if len(ci.Storing) > 0 {
+		s += " INCLUDE (" + idList(ci.Storing) + ")"
+	}
+	if ci.Interleave != "" {
+		s += " INTERLEAVE IN " + ID(ci.Interleave)
+	}
+	if ci.NullFiltered {
+		conds := make([]string, 0, len(ci.Columns))
+		for _, kp := range ci.Columns {
+			conds = append(conds, ID(kp.Column)+" IS NOT NULL")
+		}
+		s += " WHERE " + strings.Join(conds, " AND ")
+	}
+	return s, nil
+}
+
+// AlterTable renders at.
+func AlterTable(at *spansql.AlterTable) (string, error) {
+	s := "ALTER TABLE " + ID(at.Name) + " "
+	switch alt := at.Alteration.(type) {
+	case spansql.AddColumn:
+		col, err := column(alt.Def)
+		if err != nil {
+			return "", fmt.Errorf("%s.%w", at.Name, err)
+		}
+		return s + "ADD COLUMN " + col, nil
+	case spansql.DropColumn:
+		return s + "DROP COLUMN " + ID(alt.Name), nil
+	case spansql.AddConstraint:
+		c, err := constraint(alt.Constraint)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", at.Name, err)
+		}
+		return s + "ADD " + c, nil
+	case spansql.DropConstraint:
+		return s + "DROP CONSTRAINT " + ID(alt.Name), nil
+	case spansql.AlterColumn:
+		sct, ok := alt.Alteration.(spansql.SetColumnType)
+		if !ok {
+			break
+		}
+		t, err := Type(sct.Type)
+		if err != nil {
+			return "", fmt.Errorf("%s.%s: %w", at.Name, alt.Name, err)
+		}
+		nullability := "DROP NOT NULL"
+		if sct.NotNull {
+			nullability = "SET NOT NULL"
+		}
+		// type and nullability can not be altered in one statement.
+		return s + "ALTER COLUMN " + ID(alt.Name) + " TYPE " + t + ";\n" +
+			s + "ALTER COLUMN " + ID(alt.Name) + " " + nullability, nil
+	}
+	return "", fmt.Errorf("alteration %s is not supported in postgresql dialect.", at.Alteration.SQL())
+}
+
+func watch(wds []spansql.WatchDef, all bool) string {
+	if all {
+		return "ALL"
+	}
+	ss := make([]string, 0, len(wds))
+	for _, wd := range wds {
+		w := ID(wd.Table)
+		if !wd.WatchAllCols {
+			w += "(" + idList(wd.Columns) + ")"
+		}
+		ss = append(ss, w)
+	}
+	return strings.Join(ss, ", ")
+}
+
+func changeStreamOptions(opts spansql.ChangeStreamOptions) string {
+	var ss []string
+	if opts.RetentionPeriod != nil {
+		ss = append(ss, "retention_period = '"+*opts.RetentionPeriod+"'")
+	}
+	if opts.ValueCaptureType != nil {
+		ss = append(ss, "value_capture_type = '"+*opts.ValueCaptureType+"'")
+	}
+	return "(" + strings.Join(ss, ", ") + ")"
+}
+
+// CreateChangeStream renders cs. OPTIONS is rendered as WITH.
+func CreateChangeStream(cs *spansql.CreateChangeStream) string {
+	s := "CREATE CHANGE STREAM " + ID(cs.Name)
+	if cs.WatchAllTables || len(cs.Watch) > 0 {
+		s += " FOR " + watch(cs.Watch, cs.WatchAllTables)
+	}
+	if cs.Options != (spansql.ChangeStreamOptions{}) {
+		s += " WITH " + changeStreamOptions(cs.Options)
+	}
+	return s
+}
+
+// AlterChangeStream renders acs.
+func AlterChangeStream(acs *spansql.AlterChangeStream) (string, error) {
+	s := "ALTER CHANGE STREAM " + ID(acs.Name) + " "
+	switch alt := acs.Alteration.(type) {
+	case spansql.AlterWatch:
+		return s + "SET FOR " + watch(alt.Watch, alt.WatchAllTables), nil
+	case spansql.AlterChangeStreamOptions:
+		return s + "SET " + changeStreamOptions(alt.Options), nil
+	case spansql.DropChangeStreamWatch:
+		return s + "DROP FOR ALL", nil
+	}
+	return "", fmt.Errorf("alteration %s is not supported in postgresql dialect.", acs.Alteration.SQL())
+}
+
+// Stmt renders stmt.
+func Stmt(stmt spansql.DDLStmt) (string, error) {
+	switch st := stmt.(type) {
+	case *spansql.CreateTable:
+		return CreateTable(st)
+	case *spansql.CreateIndex:
+		return CreateIndex(st)
+	case *spansql.AlterTable:
+		return AlterTable(st)
+	case *spansql.DropTable:
+		return "DROP TABLE " + ID(st.Name), nil
+	case *spansql.DropIndex:
+		return "DROP INDEX " + ID(st.Name), nil
+	case *spansql.CreateChangeStream:
+		return CreateChangeStream(st), nil
+	case *spansql.AlterChangeStream:
+		return AlterChangeStream(st)
+	case *spansql.DropChangeStream:
+		return "DROP CHANGE STREAM " + ID(st.Name), nil
+	}
+	return "", fmt.Errorf("statement %s is not supported in postgresql dialect.", stmt.SQL())
+}
@@ -0,0 +1,107 @@
+package postgresql_test
+
+import (
+	"testing"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/nktks/gql-spansql/internal/postgresql"
+	"github.com/nktks/gql-spansql/internal/spantype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestType(t *testing.T) {
+	for sql, want := range map[string]string{
+		"BOOL":               "bool",
+		"INT64":              "bigint",
+		"FLOAT64":            "double precision",
+		"NUMERIC":            "numeric",
+		"STRING(MAX)":        "varchar",
+		"STRING(256)":        "varchar(256)",
+		"BYTES(MAX)":         "bytea",
+		"DATE":               "date",
+		"TIMESTAMP":          "timestamptz",
+		"JSON":               "jsonb",
+		"ARRAY<BOOL>":        "bool[]",
+		"ARRAY<INT64>":       "bigint[]",
+		"ARRAY<STRING(MAX)>": "varchar[]",
+	} {
+		ddl, err := spansql.ParseDDLStmt("CREATE TABLE T (c " + sql + ") PRIMARY KEY(c)")
+		require.NoError(t, err)
+		got, err := postgresql.Type(ddl.(*spansql.CreateTable).Columns[0].Type)
+		require.NoError(t, err)
+		require.Equal(t, want, got, sql)
+	}
+	float32Type := spantype.Float32()
+	got, err := postgresql.Type(float32Type)
+	require.NoError(t, err)
+	require.Equal(t, "real", got)
+	float32Type.Array = true
+	got, err = postgresql.Type(float32Type)
+	require.NoError(t, err)
+	require.Equal(t, "real[]", got)
+}
+
+func TestStmt(t *testing.T) {
+	ddl, err := spansql.ParseDDL("postgresql.sql", `
+CREATE TABLE User (
+  userId STRING(MAX) NOT NULL,
+  name STRING(256),
+  tags ARRAY<STRING(MAX)> NOT NULL,
+  state STRING(MAX) NOT NULL,
+  CONSTRAINT CK_User_state CHECK (state IN ("ENABLED", "DON'T")),
+) PRIMARY KEY(userId);
+CREATE TABLE Post (
+  userId STRING(MAX) NOT NULL,
+  post_id INT64 NOT NULL,
+  authorId STRING(MAX),
+  CONSTRAINT FK_Post_author FOREIGN KEY (authorId) REFERENCES User (userId) ON DELETE CASCADE,
+) PRIMARY KEY(userId, post_id),
+  INTERLEAVE IN PARENT User ON DELETE CASCADE;
+CREATE UNIQUE NULL_FILTERED INDEX PostByAuthor ON Post(authorId DESC) STORING (post_id), INTERLEAVE IN User;
+ALTER TABLE User ALTER COLUMN name STRING(MAX) NOT NULL;
+ALTER TABLE Post DROP CONSTRAINT FK_Post_author;
+DROP TABLE Post;
+CREATE CHANGE STREAM Names FOR User(name), Post OPTIONS (retention_period='7d', value_capture_type='NEW_VALUES');
+ALTER CHANGE STREAM Names SET FOR ALL;
+DROP CHANGE STREAM Names;
+`)
+	require.NoError(t, err)
+	var sqls []string
+	for _, stmt := range ddl.List {
+		sql, err := postgresql.Stmt(stmt)
+		require.NoError(t, err)
+		sqls = append(sqls, sql)
+	}
+	require.Equal(t, []string{
+		`CREATE TABLE "User" (
+  "userId" varchar NOT NULL,
+  name varchar(256),
+  tags varchar[] NOT NULL,
+  state varchar NOT NULL,
+  CONSTRAINT "CK_User_state" CHECK (state IN ('ENABLED', 'DON''T')),
+  PRIMARY KEY ("userId")
+)`,
+		`CREATE TABLE "Post" (
+  "userId" varchar NOT NULL,
+  post_id bigint NOT NULL,
+  "authorId" varchar,
+  CONSTRAINT "FK_Post_author" FOREIGN KEY ("authorId") REFERENCES "User" ("userId") ON DELETE CASCADE,
+  PRIMARY KEY ("userId", post_id)
+) INTERLEAVE IN PARENT "User" ON DELETE CASCADE`,
+		`CREATE UNIQUE INDEX "PostByAuthor" ON "Post" ("authorId" DESC) INCLUDE (post_id) INTERLEAVE IN "User" WHERE "authorId" IS NOT NULL`,
+		`ALTER TABLE "User" ALTER COLUMN name TYPE varchar;
+ALTER TABLE "User" ALTER COLUMN name SET NOT NULL`,
+		`ALTER TABLE "Post" DROP CONSTRAINT "FK_Post_author"`,
+		`DROP TABLE "Post"`,
+		`CREATE CHANGE STREAM "Names" FOR "User"(name), "Post" WITH (retention_period = '7d', value_capture_type = 'NEW_VALUES')`,
+		`ALTER CHANGE STREAM "Names" SET FOR ALL`,
+		`DROP CHANGE STREAM "Names"`,
+	}, sqls)
+}
+
+func TestCreateTable_DescKey(t *testing.T) {
+	stmt, err := spansql.ParseDDLStmt("CREATE TABLE T (c INT64 NOT NULL) PRIMARY KEY(c DESC)")
+	require.NoError(t, err)
+	_, err = postgresql.CreateTable(stmt.(*spansql.CreateTable))
+	require.Error(t, err)
+}
@@ -0,0 +1,448 @@
+package reverse
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/iancoleman/strcase"
+	"github.com/nktks/gql-spansql/internal/spantype"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+)
+
+// Converter converts spanner DDL to GraphQL SDL annotated with the directives of gql-spansql,
+// so that the SDL is converted back to the same DDL.
+type Converter struct {
+	tables      []*spansql.CreateTable
+	tableByName map[spansql.ID]*spansql.CreateTable
+	foreignKeys map[spansql.ID][]spansql.TableConstraint
+	indexes     map[spansql.ID][]*spansql.CreateIndex
+	streams     []*spansql.CreateChangeStream
+}
+
+func NewConverter(ddl *spansql.DDL) *Converter {
+	c := &Converter{
+		tableByName: map[spansql.ID]*spansql.CreateTable{},
+		foreignKeys: map[spansql.ID][]spansql.TableConstraint{},
+		indexes:     map[spansql.ID][]*spansql.CreateIndex{},
+	}
+	for _, stmt := range ddl.List {
+		switch st := stmt.(type) {
+		case *spansql.CreateTable:
+			c.tables = append(c.tables, st)
+			c.tableByName[st.Name] = st
+			for _, tc := range st.Constraints {
+				if _, ok := tc.Constraint.(spansql.ForeignKey); ok {
+					c.foreignKeys[st.Name] = append(c.foreignKeys[st.Name], tc)
+				}
+			}
+		case *spansql.AlterTable:
+			if ac, ok := st.Alteration.(spansql.AddConstraint); ok {
+				if _, ok := ac.Constraint.Constraint.(spansql.ForeignKey); ok {
+					c.foreignKeys[st.Name] = append(c.foreignKeys[st.Name], ac.Constraint)
+				}
+			}
+		case *spansql.CreateIndex:
+			c.indexes[st.Table] = append(c.indexes[st.Table], st)
+		case *spansql.CreateChangeStream:
+			c.streams = append(c.streams, st)
+		}
+	}
+	return c
+}
+
+func (c *Converter) GraphQL() (string, error) {
+	doc, err := c.SchemaDocument()
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf, formatter.WithIndent("  ")).FormatSchemaDocument(doc)
+	return buf.String(), nil
+}
+
+// SchemaDocument converts all tables to object types and declares the scalar types they use.
+func (c *Converter) SchemaDocument() (*ast.SchemaDocument, error) {
+	doc := &ast.SchemaDocument{}
+	scalars := map[string]bool{}
+	for _, ct := range c.tables {
+		def, err := c.ConvertTable(ct)
+		if err != nil {
+			return nil, err
+		}
+		doc.Definitions = append(doc.Definitions, def)
+		for _, f := range def.Fields {
+			t := f.Type
+			if t.Elem != nil {
+				t = t.Elem
+			}
+			if t.NamedType == timestampScalar || t.NamedType == dateScalar {
+				scalars[t.NamedType] = true
+			}
+		}
+	}
+	for _, s := range []string{dateScalar, timestampScalar} {
+		if scalars[s] {
+			doc.Definitions = append(doc.Definitions, &ast.Definition{
+				Kind: ast.Scalar,
+				Name: s,
+			})
+		}
+	}
+	return doc, nil
+}
+
+const (
+	timestampScalar = "Timestamp"
+	dateScalar      = "Date"
+)
+
+// relation is a relation field which replaces the columns referring to another table.
+type relation struct {
+	name       string
+	ref        spansql.ID
+	columns    []spansql.ID
+	foreignKey *spansql.TableConstraint
+}
+
+// ConvertTable converts ct to an object type.
+// foreign keys and the key of the interleave parent are converted to relation fields if the columns follow
+// the naming of gql-spansql, and the others are converted to scalar fields.
+func (c *Converter) ConvertTable(ct *spansql.CreateTable) (*ast.Definition, error) {
+	def := &ast.Definition{
+		Kind: ast.Object,
+		Name: string(ct.Name),
+	}
+	var parentKeys int
+	if il := ct.Interleave; il != nil {
+		parent, ok := c.tableByName[il.Parent]
+		if !ok {
+			return nil, fmt.Errorf("interleave parent %s of %s is not found.", il.Parent, ct.Name)
+		}
+		parentKeys = len(parent.PrimaryKey)
+		args := ast.ArgumentList{stringArg("in", string(il.Parent))}
+		if il.OnDelete == spansql.CascadeOnDelete {
+			args = append(args, enumArg("onDelete", "CASCADE"))
+		}
+		def.Directives = append(def.Directives, &ast.Directive{Name: "interleave", Arguments: args})
+	}
+	for _, ci := range c.indexes[ct.Name] {
+		def.Directives = append(def.Directives, convertIndex(ci))
+	}
+	for _, cs := range c.streams {
+		if cs.WatchAllTables {
+			return nil, fmt.Errorf("change stream %s for all tables can not be converted.", cs.Name)
+		}
+		for i, wd := range cs.Watch {
+			if wd.Table == ct.Name {
+				// the options are declared once because they are shared by the watched tables.
+				def.Directives = append(def.Directives, convertChangeStream(cs, wd, i == 0))
+			}
+		}
+	}
+
+	pkOrder := map[spansql.ID]int{}
+	pkDesc := map[spansql.ID]bool{}
+	for i, kp := range ct.PrimaryKey {
+		if i < parentKeys {
+			continue
+		}
+		pkOrder[kp.Column] = i - parentKeys + 1
+		pkDesc[kp.Column] = kp.Desc
+	}
+
+	relations := c.relations(ct, pkOrder)
+	byColumn := map[spansql.ID]*relation{}
+	for _, r := range relations {
+		for _, col := range r.columns {
+			byColumn[col] = r
+		}
+	}
+	for _, col := range ct.Columns {
+		if r, ok := byColumn[col.Name]; ok {
+			if r.columns[0] != col.Name {
+				continue
+			}
+			def.Fields = append(def.Fields, c.relationField(ct, col, r, pkOrder, pkDesc))
+			continue
+		}
+		f, err := convertColumn(col, pkOrder[col.Name] > 0)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", ct.Name, col.Name, err)
+		}
+		if order := pkOrder[col.Name]; order > 0 {
+			f.Directives = append(f.Directives, pkDirective(order, pkDesc[col.Name]))
+		}
+		def.Fields = append(def.Fields, f)
+	}
+	return def, nil
+}
+
+func (c *Converter) relationField(ct *spansql.CreateTable, col spansql.ColumnDef, r *relation, pkOrder map[spansql.ID]int, pkDesc map[spansql.ID]bool) *ast.FieldDefinition {
+	f := &ast.FieldDefinition{
+		Name: r.name,
+		Type: &ast.Type{NamedType: string(r.ref), NonNull: col.NotNull},
+	}
+	if order := pkOrder[col.Name]; order > 0 {
+		f.Directives = append(f.Directives, pkDirective(order, pkDesc[col.Name]))
+	}
+	if r.foreignKey != nil {
+		fk := r.foreignKey.Constraint.(spansql.ForeignKey)
+		var args ast.ArgumentList
+		if r.foreignKey.Name != "" && string(r.foreignKey.Name) != fmt.Sprintf("FK_%s_%s", ct.Name, r.name) {
+			args = append(args, stringArg("name", string(r.foreignKey.Name)))
+		}
+		if fk.OnDelete == spansql.CascadeOnDelete {
+			args = append(args, enumArg("onDelete", "CASCADE"))
+		}
+		f.Directives = append(f.Directives, &ast.Directive{Name: "foreignKey", Arguments: args})
+	}
+	return f
+}
+
+// relations detects relation fields of ct from its foreign keys and interleave parent.
+func (c *Converter) relations(ct *spansql.CreateTable, pkOrder map[spansql.ID]int) []*relation {
+	var relations []*relation
+	used := map[spansql.ID]bool{}
+	fieldNames := map[string]bool{}
+	for _, col := range ct.Columns {
+		fieldNames[string(col.Name)] = true
+	}
+	add := func(r *relation) {
+		if r == nil || fieldNames[r.name] {
+			return
+		}
+		for _, col := range r.columns {
+			if used[col] {
+				return
+			}
+		}
+		// a relation to multiple keys can not be a part of the primary key.
+		if len(r.columns) > 1 {
+			for _, col := range r.columns {
+				if pkOrder[col] > 0 {
+					return
+				}
+			}
+		}
+		for _, col := range r.columns {
+			used[col] = true
+		}
+		fieldNames[r.name] = true
+		relations = append(relations, r)
+	}
+	for i := range c.foreignKeys[ct.Name] {
+		tc := c.foreignKeys[ct.Name][i]
+		fk := tc.Constraint.(spansql.ForeignKey)
+		r := c.relationOf(ct, fk.Columns, fk.RefTable, fk.RefColumns)
+		if r != nil {
+			r.foreignKey = &tc
+		}
+		add(r)
+	}
+	if il := ct.Interleave; il != nil {
+		parent := c.tableByName[il.Parent]
+		var cols []spansql.ID
+		for _, kp := range parent.PrimaryKey {
+			cols = append(cols, kp.Column)
+		}
+		if len(cols) == 1 {
+			add(c.relationOf(ct, cols, parent.Name, cols))
+		}
+	}
+	return relations
+}
+
+// relationOf returns the relation field whose columns are converted to cols by gql-spansql, or nil if not found.
+func (c *Converter) relationOf(ct *spansql.CreateTable, cols []spansql.ID, refTable spansql.ID, refCols []spansql.ID) *relation {
+	ref, ok := c.tableByName[refTable]
+	if !ok || len(ref.PrimaryKey) != len(refCols) || len(cols) != len(refCols) {
+		return nil
+	}
+	for i, kp := range ref.PrimaryKey {
+		if kp.Column != refCols[i] {
+			return nil
+		}
+		col, refCol := findColumn(ct.Columns, cols[i]), findColumn(ref.Columns, refCols[i])
+		if col == nil || refCol == nil || col.Type != refCol.Type || col.Type.Array {
+			return nil
+		}
+	}
+	// see ConvertFieldName and relationColumns of the converter for the naming.
+	name := ""
+	for i, refCol := range refCols {
+		col := string(cols[i])
+		suffix := strcase.ToCamel(string(refCol))
+		if len(refCols) == 1 || isIDColumn(string(refCol), string(ref.Name)) {
+			suffix = "Id"
+		}
+		prefix := strings.TrimSuffix(col, suffix)
+		if prefix == "" || prefix == col || (name != "" && prefix != name) {
+			return nil
+		}
+		name = prefix
+	}
+	return &relation{
+		name:    name,
+		ref:     ref.Name,
+		columns: cols,
+	}
+}
+
+func isIDColumn(col, table string) bool {
+	return strcase.ToSnake(col) == "id" || strcase.ToSnake(col) == strcase.ToSnake(table+"Id")
+}
+
+// convertColumn converts col to a field. the types which @spannerType can not express, PROTO and ENUM, are errors.
+func convertColumn(col spansql.ColumnDef, isKey bool) (*ast.FieldDefinition, error) {
+	f := &ast.FieldDefinition{
+		Name: string(col.Name),
+	}
+	t, err := spantype.Normalize(col.Type)
+	if err != nil {
+		return nil, err
+	}
+	col.Type = t
+	var named string
+	switch col.Type.Base {
+	case spansql.Bool:
+		named = "Boolean"
+	case spansql.Int64:
+		named = "Int"
+	case spansql.Float64:
+		named = "Float"
+		if spantype.IsFloat32(col.Type) {
+			f.Directives = append(f.Directives, spannerTypeDirective(col.Type))
+		}
+	case spansql.Date:
+		named = dateScalar
+	case spansql.Timestamp:
+		named = timestampScalar
+	case spansql.String:
+		named = "String"
+		if isKey {
+			named = "ID"
+		}
+		if col.Type.Len != spansql.MaxLen {
+			f.Directives = append(f.Directives, spannerTypeDirective(col.Type))
+		}
+	case spansql.Bytes, spansql.Numeric, spansql.JSON:
+		named = "String"
+		f.Directives = append(f.Directives, spannerTypeDirective(col.Type))
+	default:
+		return nil, fmt.Errorf("type %s is not supported.", col.Type.SQL())
+	}
+	if col.Type.Array {
+		f.Type = &ast.Type{
+			Elem:    &ast.Type{NamedType: named, NonNull: true},
+			NonNull: col.NotNull,
+		}
+	} else {
+		f.Type = &ast.Type{NamedType: named, NonNull: col.NotNull}
+	}
+	return f, nil
+}
+
+func spannerTypeDirective(t spansql.Type) *ast.Directive {
+	t.Array = false
+	return &ast.Directive{
+		Name:      "spannerType",
+		Arguments: ast.ArgumentList{stringArg("type", spantype.SQL(t))},
+	}
+}
+
+func pkDirective(order int, desc bool) *ast.Directive {
+	args := ast.ArgumentList{{
+		Name:  "order",
+		Value: &ast.Value{Kind: ast.IntValue, Raw: strconv.Itoa(order)},
+	}}
+	if desc {
+		args = append(args, &ast.Argument{
+			Name:  "desc",
+			Value: &ast.Value{Kind: ast.BooleanValue, Raw: "true"},
+		})
+	}
+	return &ast.Directive{Name: "spannerPK", Arguments: args}
+}
+
+func convertIndex(ci *spansql.CreateIndex) *ast.Directive {
+	columns := &ast.Value{Kind: ast.ListValue}
+	for _, kp := range ci.Columns {
+		column := string(kp.Column)
+		if kp.Desc {
+			column += " DESC"
+		}
+		columns.Children = append(columns.Children, &ast.ChildValue{
+			Value: &ast.Value{Kind: ast.StringValue, Raw: column},
+		})
+	}
+	args := ast.ArgumentList{
+		stringArg("name", string(ci.Name)),
+		{Name: "columns", Value: columns},
+	}
+	if ci.Unique {
+		args = append(args, &ast.Argument{Name: "unique", Value: &ast.Value{Kind: ast.BooleanValue, Raw: "true"}})
+	}
+	if ci.NullFiltered {
+		args = append(args, &ast.Argument{Name: "nullFiltered", Value: &ast.Value{Kind: ast.BooleanValue, Raw: "true"}})
+	}
+	if len(ci.Storing) > 0 {
+		storing := &ast.Value{Kind: ast.ListValue}
+		for _, s := range ci.Storing {
+			storing.Children = append(storing.Children, &ast.ChildValue{
+				Value: &ast.Value{Kind: ast.StringValue, Raw: string(s)},
+			})
+		}
+		args = append(args, &ast.Argument{Name: "storing", Value: storing})
+	}
+	if ci.Interleave != "" {
+		args = append(args, stringArg("interleaveIn", string(ci.Interleave)))
+	}
+	return &ast.Directive{Name: "index", Arguments: args}
+}
+
+func convertChangeStream(cs *spansql.CreateChangeStream, wd spansql.WatchDef, withOptions bool) *ast.Directive {
+	args := ast.ArgumentList{stringArg("name", string(cs.Name))}
+	if !wd.WatchAllCols {
+		columns := &ast.Value{Kind: ast.ListValue}
+		for _, col := range wd.Columns {
+			columns.Children = append(columns.Children, &ast.ChildValue{
+				Value: &ast.Value{Kind: ast.StringValue, Raw: string(col)},
+			})
+		}
+		args = append(args, &ast.Argument{Name: "columns", Value: columns})
+	}
+	if withOptions && cs.Options.RetentionPeriod != nil {
+		args = append(args, stringArg("retentionPeriod", *cs.Options.RetentionPeriod))
+	}
+	if withOptions && cs.Options.ValueCaptureType != nil {
+		args = append(args, stringArg("valueCaptureType", *cs.Options.ValueCaptureType))
+	}
+	return &ast.Directive{Name: "changeStream", Arguments: args}
+}
+
+func stringArg(name, value string) *ast.Argument {
+	return &ast.Argument{
+		Name:  name,
+		Value: &ast.Value{Kind: ast.StringValue, Raw: value},
+	}
+}
+
+func enumArg(name, value string) *ast.Argument {
+	return &ast.Argument{
+		Name:  name,
+		Value: &ast.Value{Kind: ast.EnumValue, Raw: value},
+	}
+}
+
+func findColumn(cols []spansql.ColumnDef, name spansql.ID) *spansql.ColumnDef {
+	for i := range cols {
+		if cols[i].Name == name {
+			return &cols[i]
+		}
+	}
+	return nil
+}
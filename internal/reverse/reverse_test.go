@@ -0,0 +1,111 @@
+package reverse_test
+
+import (
+	_ "embed"
+	"testing"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/nktks/gql-spansql/internal/converter"
+	"github.com/nktks/gql-spansql/internal/reverse"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+//go:embed testdata/round_trip.sql
+var roundTripBody []byte
+
+func TestConverter_GraphQL(t *testing.T) {
+	ddl, err := spansql.ParseDDL("round_trip.sql", string(roundTripBody))
+	require.NoError(t, err)
+	gql, err := reverse.NewConverter(ddl).GraphQL()
+	require.NoError(t, err)
+	require.Equal(t, `type Blob @changeStream(name: "Everything", retentionPeriod: "7d") {
+  blobId: ID! @spannerType(type: "STRING(36)") @spannerPK(order: 1)
+  data: String! @spannerType(type: "BYTES(MAX)")
+  chunks: [String!] @spannerType(type: "BYTES(1024)")
+  price: String @spannerType(type: "NUMERIC")
+  meta: String @spannerType(type: "JSON")
+  name: String @spannerType(type: "STRING(256)")
+  day: Date
+}
+type Team {
+  teamId: ID! @spannerPK(order: 1)
+  name: String!
+}
+type User @index(name: "UserByAge", columns: ["age DESC"], storing: ["tags"]) @changeStream(name: "Everything", columns: ["age","tags"]) {
+  userId: ID! @spannerPK(order: 1)
+  team: Team! @foreignKey
+  age: Int
+  tags: [String!]!
+  createdAt: Timestamp!
+}
+type Post @interleave(in: "User", onDelete: CASCADE) {
+  user: User!
+  postId: Int! @spannerPK(order: 1, desc: true)
+  title: String!
+}
+scalar Date
+scalar Timestamp
+`, gql)
+
+	t.Run("round trip", func(t *testing.T) {
+		s, err := gqlparser.LoadSchema(converter.Directives, &ast.Source{Input: gql})
+		require.NoError(t, err)
+		c, err := converter.NewConverter(s, false, "", "", "", "")
+		require.NoError(t, err)
+		sql, err := c.SpannerSQL()
+		require.NoError(t, err)
+		require.Equal(t, string(roundTripBody), sql)
+	})
+}
+
+func TestConverter_ConvertTable(t *testing.T) {
+	ddl, err := spansql.ParseDDL("convert_table.sql", `
+CREATE TABLE User (
+  id STRING(MAX) NOT NULL,
+) PRIMARY KEY(id);
+CREATE TABLE Event (
+  eventId STRING(36) NOT NULL,
+  payload BYTES(MAX),
+  amount NUMERIC,
+  ownerUserId STRING(MAX),
+  CONSTRAINT FK_Owner FOREIGN KEY (ownerUserId) REFERENCES User (id) ON DELETE CASCADE,
+) PRIMARY KEY(eventId);
+`)
+	require.NoError(t, err)
+	c := reverse.NewConverter(ddl)
+	def, err := c.ConvertTable(ddl.List[1].(*spansql.CreateTable))
+	require.NoError(t, err)
+	require.Equal(t, "eventId", def.Fields[0].Name)
+	require.Equal(t, "ID!", def.Fields[0].Type.String())
+	require.Equal(t, `"STRING(36)"`, def.Fields[0].Directives.ForName("spannerType").Arguments.ForName("type").Value.String())
+	require.Equal(t, `"BYTES(MAX)"`, def.Fields[1].Directives.ForName("spannerType").Arguments.ForName("type").Value.String())
+	require.Equal(t, `"NUMERIC"`, def.Fields[2].Directives.ForName("spannerType").Arguments.ForName("type").Value.String())
+	require.Equal(t, "ownerUser", def.Fields[3].Name)
+	require.Equal(t, "User", def.Fields[3].Type.String())
+	fk := def.Fields[3].Directives.ForName("foreignKey")
+	require.Equal(t, `"FK_Owner"`, fk.Arguments.ForName("name").Value.String())
+	require.Equal(t, "CASCADE", fk.Arguments.ForName("onDelete").Value.String())
+}
+
+func TestConverter_ConvertTable_Types(t *testing.T) {
+	ddl, err := spansql.ParseDDL("types.sql", `
+CREATE TABLE Item (
+  itemId STRING(MAX) NOT NULL,
+  score FLOAT32,
+  detail examples.ItemDetail,
+) PRIMARY KEY(itemId);
+`)
+	require.NoError(t, err)
+	ct := ddl.List[0].(*spansql.CreateTable)
+	c := reverse.NewConverter(ddl)
+	_, err = c.ConvertTable(ct)
+	require.EqualError(t, err, "Item.detail: type `examples.ItemDetail` is not supported.")
+
+	ct.Columns = ct.Columns[:2]
+	def, err := c.ConvertTable(ct)
+	require.NoError(t, err)
+	require.Equal(t, "Float", def.Fields[1].Type.String())
+	require.Equal(t, `"FLOAT32"`, def.Fields[1].Directives.ForName("spannerType").Arguments.ForName("type").Value.String())
+}
@@ -0,0 +1,29 @@
+CREATE TABLE Blob (
+  blobId STRING(36) NOT NULL,
+  data BYTES(MAX) NOT NULL,
+  chunks ARRAY<BYTES(1024)>,
+  price NUMERIC,
+  meta JSON,
+  name STRING(256),
+  day DATE,
+) PRIMARY KEY(blobId);
+CREATE TABLE Team (
+  teamId STRING(MAX) NOT NULL,
+  name STRING(MAX) NOT NULL,
+) PRIMARY KEY(teamId);
+CREATE TABLE User (
+  userId STRING(MAX) NOT NULL,
+  teamId STRING(MAX) NOT NULL,
+  age INT64,
+  tags ARRAY<STRING(MAX)> NOT NULL,
+  createdAt TIMESTAMP NOT NULL,
+  CONSTRAINT FK_User_team FOREIGN KEY (teamId) REFERENCES Team (teamId) ON DELETE NO ACTION,
+) PRIMARY KEY(userId);
+CREATE INDEX UserByAge ON User(age DESC) STORING (tags);
+CREATE TABLE Post (
+  userId STRING(MAX) NOT NULL,
+  postId INT64 NOT NULL,
+  title STRING(MAX) NOT NULL,
+) PRIMARY KEY(userId, postId DESC),
+  INTERLEAVE IN PARENT User ON DELETE CASCADE;
+CREATE CHANGE STREAM Everything FOR Blob, User(age, tags) OPTIONS (retention_period='7d');
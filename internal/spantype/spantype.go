@@ -0,0 +1,105 @@
+// Package spantype provides the spanner types which spansql can not express yet.
+package spantype
+
+import (
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/spanner/spansql"
+)
+
+// float32Ref marks FLOAT64 as FLOAT32. spansql has no type base of FLOAT32,
+// and it reads ProtoRef only for PROTO and ENUM.
+const float32Ref = "FLOAT32"
+
+// Float32 returns the FLOAT32 type.
+func Float32() spansql.Type {
+	return spansql.Type{Base: spansql.Float64, ProtoRef: float32Ref}
+}
+
+// IsFloat32 reports whether t is FLOAT32 or ARRAY<FLOAT32>.
+func IsFloat32(t spansql.Type) bool {
+	return t.Base == spansql.Float64 && t.ProtoRef == float32Ref
+}
+
+// Normalize returns FLOAT32 if t is FLOAT32 parsed by spansql, which parses it as the PROTO named FLOAT32.
+// spansql also takes the following NOT NULL and options into the name, which can not be recovered, so they are errors.
+// the other types are returned as they are.
+func Normalize(t spansql.Type) (spansql.Type, error) {
+	if t.Base != spansql.Proto || !strings.HasPrefix(strings.ToUpper(t.ProtoRef), float32Ref) {
+		return t, nil
+	}
+	if len(t.ProtoRef) != len(float32Ref) {
+		return spansql.Type{}, fmt.Errorf("FLOAT32 followed by NOT NULL or options can not be parsed by spansql.")
+	}
+	f := Float32()
+	f.Array = t.Array
+	return f, nil
+}
+
+// HasFloat32 reports whether stmt has FLOAT32 columns, which spansql.DDLStmt.SQL renders as FLOAT64.
+func HasFloat32(stmt spansql.DDLStmt) bool {
+	switch st := stmt.(type) {
+	case *spansql.CreateTable:
+		for _, cd := range st.Columns {
+			if IsFloat32(cd.Type) {
+				return true
+			}
+		}
+	case *spansql.AlterTable:
+		switch alt := st.Alteration.(type) {
+		case spansql.AddColumn:
+			return IsFloat32(alt.Def.Type)
+		case spansql.AlterColumn:
+			sct, ok := alt.Alteration.(spansql.SetColumnType)
+			return ok && IsFloat32(sct.Type)
+		}
+	}
+	return false
+}
+
+// SQL renders t in GoogleSQL like spansql.Type.SQL, including FLOAT32.
+func SQL(t spansql.Type) string {
+	if !IsFloat32(t) {
+		return t.SQL()
+	}
+	if t.Array {
+		return "ARRAY<FLOAT32>"
+	}
+	return "FLOAT32"
+}
+
+// ColumnSQL renders cd in GoogleSQL like spansql.ColumnDef.SQL, including FLOAT32.
+func ColumnSQL(cd spansql.ColumnDef) string {
+	sql := cd.SQL()
+	if !IsFloat32(cd.Type) {
+		return sql
+	}
+	return cd.Name.SQL() + " " + SQL(cd.Type) + strings.TrimPrefix(sql, cd.Name.SQL()+" "+cd.Type.SQL())
+}
+
+// StmtSQL renders stmt in GoogleSQL like spansql.DDLStmt.SQL, including the FLOAT32 columns.
+func StmtSQL(stmt spansql.DDLStmt) string {
+	sql := stmt.SQL()
+	switch st := stmt.(type) {
+	case *spansql.CreateTable:
+		for _, cd := range st.Columns {
+			if IsFloat32(cd.Type) {
+				sql = strings.Replace(sql, "\n  "+cd.SQL()+",\n", "\n  "+ColumnSQL(cd)+",\n", 1)
+			}
+		}
+	case *spansql.AlterTable:
+		switch alt := st.Alteration.(type) {
+		case spansql.AddColumn:
+			if IsFloat32(alt.Def.Type) {
+				sql = strings.Replace(sql, alt.Def.SQL(), ColumnSQL(alt.Def), 1)
+			}
+		case spansql.AlterColumn:
+			if sct, ok := alt.Alteration.(spansql.SetColumnType); ok && IsFloat32(sct.Type) {
+				prefix := "ALTER COLUMN " + alt.Name.SQL() + " "
+				sql = strings.Replace(sql, prefix+sct.Type.SQL(), prefix+SQL(sct.Type), 1)
+			}
+		}
+	}
+	return sql
+}
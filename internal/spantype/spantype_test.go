@@ -0,0 +1,69 @@
+package spantype_test
+
+import (
+	"testing"
+
+	"cloud.google.com/go/spanner/spansql"
+	"github.com/nktks/gql-spansql/internal/spantype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQL(t *testing.T) {
+	array := spantype.Float32()
+	array.Array = true
+	require.Equal(t, "FLOAT32", spantype.SQL(spantype.Float32()))
+	require.Equal(t, "ARRAY<FLOAT32>", spantype.SQL(array))
+	require.Equal(t, "FLOAT64", spantype.SQL(spansql.Type{Base: spansql.Float64}))
+	require.False(t, spantype.IsFloat32(spansql.Type{Base: spansql.Float64}))
+}
+
+func TestStmtSQL(t *testing.T) {
+	score := spansql.ColumnDef{Name: "score", Type: spantype.Float32(), NotNull: true}
+	for _, tc := range []struct {
+		stmt spansql.DDLStmt
+		want string
+	}{
+		{
+			stmt: &spansql.CreateTable{
+				Name: "Item",
+				Columns: []spansql.ColumnDef{
+					{Name: "itemId", Type: spansql.Type{Base: spansql.Int64}, NotNull: true},
+					{Name: "price", Type: spansql.Type{Base: spansql.Float64}},
+					score,
+				},
+				PrimaryKey: []spansql.KeyPart{{Column: "itemId"}},
+			},
+			want: `CREATE TABLE Item (
+  itemId INT64 NOT NULL,
+  price FLOAT64,
+  score FLOAT32 NOT NULL,
+) PRIMARY KEY(itemId)`,
+		},
+		{
+			stmt: &spansql.AlterTable{Name: "Item", Alteration: spansql.AddColumn{Def: score}},
+			want: "ALTER TABLE Item ADD COLUMN score FLOAT32 NOT NULL",
+		},
+		{
+			stmt: &spansql.AlterTable{Name: "Item", Alteration: spansql.AlterColumn{
+				Name:       "score",
+				Alteration: spansql.SetColumnType{Type: spantype.Float32()},
+			}},
+			want: "ALTER TABLE Item ALTER COLUMN score FLOAT32",
+		},
+	} {
+		require.Equal(t, tc.want, spantype.StmtSQL(tc.stmt))
+		require.True(t, spantype.HasFloat32(tc.stmt))
+	}
+	require.False(t, spantype.HasFloat32(&spansql.DropTable{Name: "Item"}))
+}
+
+func TestNormalize(t *testing.T) {
+	got, err := spantype.Normalize(spansql.Type{Base: spansql.Proto, ProtoRef: "FLOAT32"})
+	require.NoError(t, err)
+	require.Equal(t, spantype.Float32(), got)
+	got, err = spantype.Normalize(spansql.Type{Base: spansql.Proto, ProtoRef: "examples.Item"})
+	require.NoError(t, err)
+	require.Equal(t, spansql.Type{Base: spansql.Proto, ProtoRef: "examples.Item"}, got)
+	_, err = spantype.Normalize(spansql.Type{Base: spansql.Proto, ProtoRef: "FLOAT32NOTNULL"})
+	require.Error(t, err)
+}